@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"argumentum-backend/internal/openapi"
+	"argumentum-backend/models"
+)
+
+const (
+	tagAuth     = "Autenticação"
+	tagAccount  = "Conta"
+	tagAPIKeys  = "Chaves de API"
+	tagPetition = "Petições"
+	tagTeams    = "Equipes"
+	tagDocs     = "Documentos"
+	tagAdmin    = "Administração"
+	tagOps      = "Operação"
+)
+
+var tokenQuery = openapi.Parameter{
+	Name: "token", In: "query", Required: true,
+	Description: "Token do link assinado",
+	Schema:      &openapi.Schema{Type: "string"},
+}
+
+// petitionListQuery are the parameters of GET /petitions
+// (models.PetitionListQuery).
+var petitionListQuery = []openapi.Parameter{
+	{Name: "status", In: "query", Description: "Filtra pelo status", Schema: &openapi.Schema{Type: "string"}},
+	{Name: "page", In: "query", Description: "Página, a partir de 1", Schema: &openapi.Schema{Type: "integer"}},
+	{Name: "limit", In: "query", Description: "Itens por página (padrão 20, até 100)", Schema: &openapi.Schema{Type: "integer"}},
+	{Name: "order", In: "query", Description: "Ordem por data de criação; mais recentes primeiro por padrão",
+		Schema: &openapi.Schema{Type: "string", Enum: []string{"asc", "desc"}}},
+}
+
+// idempotent documents the Idempotency-Key header of the routes behind
+// middleware.Idempotency, with its 409, 413 and 422 answers.
+func idempotent(r openapi.Route) openapi.Route {
+	r.Params = append(r.Params, openapi.Parameter{
+		Name: models.IdempotencyKeyHeader, In: "header",
+		Description: "Chave única da operação (até 255 caracteres). Repetições com a mesma chave em 24h recebem a " +
+			"primeira resposta, com Idempotent-Replayed: true; a mesma chave com outro corpo é recusada com 422",
+		Schema: &openapi.Schema{Type: "string"},
+	})
+	r.Errors = append(r.Errors, http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity)
+	return r
+}
+
+// apiSpec describes every route registered in routes. Request and response
+// schemas come from the models, so a field added there shows up here
+// without further edits.
+func apiSpec() *openapi.Document {
+	b := openapi.NewBuilder(openapi.Info{
+		Title:   "Argumentum API",
+		Version: "1.0.0",
+		Description: "Erros seguem models.ApiResponse: `code` estável, `error` no idioma do " +
+			"Accept-Language (pt-BR ou en) e `fields` para corpos inválidos.",
+	}, models.ApiResponse{})
+
+	b.Tag(tagAuth, "Login, sessões, 2FA e senhas")
+	b.Tag(tagAccount, "Perfil, exclusão de conta (LGPD) e exportação de dados")
+	b.Tag(tagAPIKeys, "Chaves para integrações")
+	b.Tag(tagPetition, "Petições e configurações")
+	b.Tag(tagTeams, "Equipes e saldo de tokens")
+	b.Tag(tagDocs, "Documentos e storage")
+	b.Tag(tagAdmin, "Somente administradores")
+	b.Tag(tagOps, "Saúde, métricas e documentação")
+
+	for _, r := range apiRoutes() {
+		// Authenticated routes outside /auth go through the rate limiter
+		if r.Auth != openapi.Public && !strings.HasPrefix(r.Path, "/auth/") {
+			r.Errors = append(r.Errors, http.StatusTooManyRequests)
+		}
+		b.Add(r)
+	}
+	return b.Document()
+}
+
+func apiRoutes() []openapi.Route {
+	const (
+		post   = http.MethodPost
+		get    = http.MethodGet
+		put    = http.MethodPut
+		delete = http.MethodDelete
+	)
+	session, either := openapi.Session, openapi.SessionOrKey
+	tooMany := []int{http.StatusTooManyRequests}
+
+	return []openapi.Route{
+		// Autenticação
+		{Method: post, Path: "/auth/login", Tag: tagAuth, Summary: "Login com email e senha",
+			Description: "Contas com 2FA recebem um mfaToken em vez da sessão; conclua em /auth/login/mfa.",
+			Request:     models.LoginRequest{}, Response: openapi.OneOf(models.AuthResponse{}, models.MFAChallengeResponse{}),
+			Errors: []int{http.StatusUnauthorized, http.StatusTooManyRequests}},
+		{Method: post, Path: "/auth/login/mfa", Tag: tagAuth, Summary: "Segunda etapa do login com código TOTP ou de recuperação",
+			Request: models.MFALoginRequest{}, Response: models.AuthResponse{}, Errors: []int{http.StatusUnauthorized, http.StatusTooManyRequests}},
+		{Method: post, Path: "/auth/register", Tag: tagAuth, Summary: "Cadastro",
+			Request: models.RegisterRequest{}, Response: models.AuthResponse{}, Status: http.StatusCreated, Errors: []int{http.StatusConflict}},
+		{Method: post, Path: "/auth/logout", Tag: tagAuth, Summary: "Encerra a sessão atual", Auth: session},
+		{Method: post, Path: "/auth/logout/all", Tag: tagAuth, Summary: "Encerra todas as sessões", Auth: session},
+		{Method: get, Path: "/auth/sessions", Tag: tagAuth, Summary: "Lista as sessões ativas", Auth: session,
+			Response: []models.SessionInfo{}},
+		{Method: delete, Path: "/auth/sessions/:id", Tag: tagAuth, Summary: "Encerra uma sessão", Auth: session,
+			Errors: []int{http.StatusNotFound}},
+		{Method: post, Path: "/auth/mfa/enroll", Tag: tagAuth, Summary: "Inicia o cadastro de 2FA (TOTP)", Auth: session,
+			Response: models.MFAEnrollResponse{}, Errors: []int{http.StatusConflict}},
+		{Method: post, Path: "/auth/mfa/verify", Tag: tagAuth, Summary: "Ativa o 2FA e devolve os códigos de recuperação", Auth: session,
+			Request: models.MFACodeRequest{}, Response: models.MFARecoveryCodesResponse{}, Errors: []int{http.StatusConflict, http.StatusNotFound}},
+		{Method: post, Path: "/auth/mfa/disable", Tag: tagAuth, Summary: "Desativa o 2FA", Auth: session,
+			Request: models.MFACodeRequest{}, Errors: []int{http.StatusConflict}},
+		{Method: post, Path: "/auth/refresh", Tag: tagAuth, Summary: "Troca o refresh token por uma nova sessão",
+			Request: models.RefreshRequest{}, Response: models.AuthResponse{}, Errors: []int{http.StatusUnauthorized}},
+		{Method: post, Path: "/auth/reset-password", Tag: tagAuth, Summary: "Envia o email de recuperação de senha",
+			Request: models.ResetPasswordRequest{}, Errors: tooMany},
+		{Method: post, Path: "/auth/reset-password/confirm", Tag: tagAuth, Summary: "Define a nova senha com o token do email",
+			Request: models.ConfirmResetPasswordRequest{}},
+		{Method: put, Path: "/auth/password", Tag: tagAuth, Summary: "Altera a senha e encerra as outras sessões", Auth: session,
+			Request: models.ChangePasswordRequest{}, Response: models.AuthResponse{}},
+		{Method: get, Path: "/.well-known/jwks.json", Tag: tagAuth, Summary: "Chaves públicas de verificação dos tokens (JWKS)",
+			Raw: true, Response: openapi.Object(map[string]*openapi.Schema{"keys": openapi.Array(&openapi.Schema{Type: "object"})})},
+
+		// Conta
+		{Method: get, Path: "/profile", Tag: tagAccount, Summary: "Perfil do usuário", Auth: either, Scope: models.ScopeProfileRead,
+			Response: models.User{}, Errors: []int{http.StatusNotFound}},
+		{Method: put, Path: "/profile", Tag: tagAccount, Summary: "Atualiza o perfil (ainda não implementado)", Auth: session,
+			Errors: []int{http.StatusNotImplemented}},
+		{Method: delete, Path: "/profile", Tag: tagAccount, Summary: "Pede a exclusão da conta após o prazo de carência", Auth: session,
+			Request: models.DeleteAccountRequest{}, Response: models.AccountDeletionResponse{}, Status: http.StatusAccepted,
+			Errors: []int{http.StatusConflict, http.StatusForbidden}},
+		{Method: get, Path: "/profile/deletion", Tag: tagAccount, Summary: "Pedido de exclusão em andamento", Auth: session,
+			Response: models.AccountDeletionResponse{}, Errors: []int{http.StatusNotFound}},
+		{Method: delete, Path: "/profile/deletion", Tag: tagAccount, Summary: "Cancela o pedido de exclusão", Auth: session,
+			Response: models.AccountDeletionResponse{}, Errors: []int{http.StatusNotFound, http.StatusConflict}},
+		{Method: get, Path: "/account-deletions/:id/receipt", Tag: tagAccount, Summary: "Comprovante de exclusão (com o receiptToken)",
+			Params: []openapi.Parameter{tokenQuery}, Response: models.AccountDeletionResponse{}, Errors: []int{http.StatusNotFound}},
+		idempotent(openapi.Route{Method: post, Path: "/profile/export", Tag: tagAccount, Summary: "Inicia a exportação dos dados (ZIP)", Auth: session,
+			Response: models.DataExportResponse{}, Status: http.StatusAccepted, Errors: []int{http.StatusConflict, http.StatusServiceUnavailable}}),
+		{Method: get, Path: "/profile/export/:id", Tag: tagAccount, Summary: "Status da exportação e link de download", Auth: session,
+			Response: models.DataExportResponse{}, Errors: []int{http.StatusNotFound}},
+		{Method: get, Path: "/exports/:id/download", Tag: tagAccount, Summary: "Redireciona para o download do ZIP pelo link assinado",
+			Params: []openapi.Parameter{tokenQuery}, Raw: true, Status: http.StatusFound, Errors: []int{http.StatusNotFound}},
+
+		// Chaves de API
+		{Method: get, Path: "/api-keys", Tag: tagAPIKeys, Summary: "Lista as chaves de API", Auth: session,
+			Response: []models.APIKeyInfo{}},
+		{Method: post, Path: "/api-keys", Tag: tagAPIKeys, Summary: "Cria uma chave; o segredo só aparece nesta resposta", Auth: session,
+			Request: models.CreateAPIKeyRequest{}, Response: models.CreateAPIKeyResponse{}, Status: http.StatusCreated,
+			Errors: []int{http.StatusForbidden}},
+		{Method: put, Path: "/api-keys/:id", Tag: tagAPIKeys, Summary: "Renomeia a chave ou altera suas permissões", Auth: session,
+			Request: models.UpdateAPIKeyRequest{}, Response: models.APIKeyInfo{}, Errors: []int{http.StatusNotFound}},
+		{Method: delete, Path: "/api-keys/:id", Tag: tagAPIKeys, Summary: "Revoga a chave", Auth: session,
+			Errors: []int{http.StatusNotFound}},
+
+		// Petições
+		{Method: get, Path: "/petitions", Tag: tagPetition, Summary: "Lista as petições do usuário e das suas equipes", Auth: either, Scope: models.ScopePetitionsRead,
+			Description: "Administradores veem todas as petições; chaves de equipe, apenas as da equipe.",
+			Params:      petitionListQuery, Response: models.PetitionList{}, Errors: []int{http.StatusBadRequest}},
+		idempotent(openapi.Route{Method: post, Path: "/petitions", Tag: tagPetition, Summary: "Cria uma petição numa equipe", Auth: either, Scope: models.ScopePetitionsWrite,
+			Description: fmt.Sprintf("Debita %d tokens do saldo do dono da equipe; sem saldo suficiente responde 402 (insufficient_tokens).", models.PetitionCost),
+			Request:     models.CreatePetitionRequest{}, Response: models.Petition{}, Status: http.StatusCreated,
+			Errors: []int{http.StatusPaymentRequired, http.StatusForbidden, http.StatusNotFound}}),
+		{Method: get, Path: "/petitions/:id", Tag: tagPetition, Summary: "Petição com autor, anexos, comentários e documentos", Auth: either, Scope: models.ScopePetitionsRead,
+			Response: models.PetitionDetail{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: put, Path: "/petitions/:id", Tag: tagPetition, Summary: "Atualiza os campos editáveis da petição", Auth: either, Scope: models.ScopePetitionsWrite,
+			Request: models.UpdatePetitionRequest{}, Response: models.Petition{}, Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: delete, Path: "/petitions/:id", Tag: tagPetition, Summary: "Remove a petição (autor ou administrador)", Auth: either, Scope: models.ScopePetitionsWrite,
+			Errors: []int{http.StatusForbidden, http.StatusNotFound}},
+		{Method: get, Path: "/petition-settings", Tag: tagPetition, Summary: "Configurações de petição", Auth: either, Scope: models.ScopePetitionsRead,
+			Response: &openapi.Schema{Type: "object"}},
+		{Method: put, Path: "/petition-settings", Tag: tagPetition, Summary: "Atualiza as configurações (ainda não implementado)", Auth: either, Scope: models.ScopePetitionsWrite,
+			Errors: []int{http.StatusNotImplemented}},
+
+		// Equipes
+		{Method: get, Path: "/teams", Tag: tagTeams, Summary: "Lista as equipes", Auth: either, Scope: models.ScopeTeamsRead,
+			Response: []models.Team{}},
+		idempotent(openapi.Route{Method: post, Path: "/teams", Tag: tagTeams, Summary: "Cria uma equipe (ainda não implementado)", Auth: session,
+			Response: models.Team{}, Status: http.StatusCreated, Errors: []int{http.StatusNotImplemented}}),
+		{Method: get, Path: "/teams/:id", Tag: tagTeams, Summary: "Equipe (ainda não implementado)", Auth: either, Scope: models.ScopeTeamsRead,
+			Response: models.Team{}, Errors: []int{http.StatusNotImplemented}},
+		{Method: put, Path: "/teams/:id", Tag: tagTeams, Summary: "Atualiza a equipe; somente o owner (ainda não implementado)", Auth: session,
+			Response: models.Team{}, Errors: []int{http.StatusForbidden, http.StatusNotImplemented}},
+		{Method: delete, Path: "/teams/:id", Tag: tagTeams, Summary: "Remove a equipe; somente o owner (ainda não implementado)", Auth: session,
+			Errors: []int{http.StatusForbidden, http.StatusNotImplemented}},
+		{Method: get, Path: "/teams/:id/token-balance", Tag: tagTeams, Summary: "Saldo de tokens do owner da equipe", Auth: either, Scope: models.ScopeTeamsRead,
+			Response: openapi.Object(map[string]*openapi.Schema{"tokens": {Type: "integer"}}), Errors: []int{http.StatusNotFound}},
+
+		// Documentos
+		{Method: get, Path: "/documents", Tag: tagDocs, Summary: "Lista os documentos", Auth: either, Scope: models.ScopeDocumentsRead,
+			Response: []models.PetitionDocument{}},
+		{Method: post, Path: "/documents/upload", Tag: tagDocs, Summary: "Envia um documento (ainda não implementado)", Auth: either, Scope: models.ScopeDocumentsWrite,
+			Errors: []int{http.StatusNotImplemented}},
+		{Method: delete, Path: "/documents/:id", Tag: tagDocs, Summary: "Remove um documento (ainda não implementado)", Auth: either, Scope: models.ScopeDocumentsWrite,
+			Errors: []int{http.StatusNotImplemented}},
+		{Method: post, Path: "/storage/signed-url", Tag: tagDocs, Summary: "URL assinada de um arquivo (ainda não implementado)", Auth: either, Scope: models.ScopeDocumentsRead,
+			Errors: []int{http.StatusNotImplemented}},
+		{Method: post, Path: "/storage/delete", Tag: tagDocs, Summary: "Remove um arquivo do storage (ainda não implementado)", Auth: either, Scope: models.ScopeDocumentsWrite,
+			Errors: []int{http.StatusNotImplemented}},
+
+		// Administração
+		{Method: get, Path: "/admin/stats", Tag: tagAdmin, Summary: "Estatísticas da plataforma", Auth: session,
+			Response: models.AdminStats{}, Errors: []int{http.StatusForbidden}},
+
+		// Operação
+		{Method: get, Path: "/health", Tag: tagOps, Summary: "Alias de /health/live", Raw: true, Response: healthSchema},
+		{Method: get, Path: "/health/live", Tag: tagOps, Summary: "O processo está respondendo", Raw: true, Response: healthSchema},
+		{Method: get, Path: "/health/ready", Tag: tagOps, Summary: "GoTrue, PostgREST e Storage acessíveis; 503 quando não",
+			Raw: true, Response: openapi.Object(map[string]*openapi.Schema{
+				"status": {Type: "string"},
+				"checks": {Type: "object", AdditionalProperties: openapi.Object(map[string]*openapi.Schema{
+					"status":    {Type: "string", Enum: []string{"up", "down"}},
+					"latencyMs": {Type: "integer"},
+					"checkedAt": {Type: "string", Format: "date-time"},
+				})},
+			})},
+		{Method: get, Path: "/metrics", Tag: tagOps, Summary: "Métricas Prometheus (Bearer METRICS_TOKEN quando configurado)",
+			Raw: true, ContentType: "text/plain", Response: &openapi.Schema{Type: "string"}},
+		{Method: get, Path: "/openapi.json", Tag: tagOps, Summary: "Esta especificação",
+			Raw: true, Response: &openapi.Schema{Type: "object"}},
+		{Method: get, Path: "/docs/*filepath", Tag: tagOps, Summary: "Documentação interativa (Swagger UI)",
+			Raw: true, ContentType: "text/html"},
+	}
+}
+
+var healthSchema = openapi.Object(map[string]*openapi.Schema{
+	"status":  {Type: "string"},
+	"message": {Type: "string"},
+})
@@ -0,0 +1,483 @@
+package handlers
+
+import (
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/i18n"
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// erasedCategories lists what delete_user_data and eraseAccount remove, as
+// reported in the receipt.
+var erasedCategories = []string{
+	"auth_user",
+	"profile",
+	"petitions",
+	"petition_documents",
+	"petition_attachments",
+	"petition_comments",
+	"petition_reviews",
+	"petition_settings",
+	"token_transactions",
+	"user_tokens",
+	"team_memberships",
+	"team_invites",
+	"sessions",
+	"api_keys",
+	"mfa",
+	"data_exports",
+	"storage_objects",
+}
+
+// RequestAccountDeletion schedules the erasure of the user's account after the
+// grace period. The password is asked again, and teams with other members
+// must be handed over first.
+func (h *AuthHandler) RequestAccountDeletion(c *gin.Context) {
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Invalid(c, err)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	ctx := c.Request.Context()
+
+	email, err := h.repos.Identity.GetEmail(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading auth user", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	attempt, throttled := h.throttled(c, h.loginGuard, email)
+	if throttled {
+		return
+	}
+	if err := h.checkPassword(ctx, email, req.Password); err != nil {
+		if isInvalidCredentials(err) {
+			apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeIncorrectPassword)
+			return
+		}
+		h.forgive(c, h.loginGuard, attempt)
+		slog.ErrorContext(ctx, "Error checking password", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	h.forgive(c, h.loginGuard, attempt)
+
+	if _, err := h.accountDeletions.GetPending(ctx, userID); err == nil {
+		apierror.Respond(c, http.StatusConflict, models.ErrCodeDeletionAlreadyRequested)
+		return
+	} else if !errors.Is(err, store.ErrNotFound) {
+		slog.ErrorContext(ctx, "Error loading deletion request", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	conflicts, _, err := h.ownedTeams(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error checking team ownership", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	if len(conflicts) > 0 {
+		resp := apierror.New(c, models.ErrCodeTeamOwnershipPending)
+		resp.Data = conflicts
+		c.JSON(http.StatusConflict, resp)
+		return
+	}
+
+	receiptToken, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error generating receipt token", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	now := time.Now().UTC()
+	deletion := &models.AccountDeletion{
+		ID:               utils.GenerateID(),
+		UserID:           userID,
+		EmailSHA256:      utils.HashToken(strings.ToLower(email)),
+		Reason:           req.Reason,
+		Status:           models.AccountDeletionPending,
+		ReceiptTokenHash: utils.HashToken(receiptToken),
+		RequestedAt:      now,
+		ScheduledFor:     now.Add(h.deletionGrace),
+	}
+	if err := h.accountDeletions.Create(ctx, deletion); err != nil {
+		slog.ErrorContext(ctx, "Error creating deletion request", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeDeletionRequestFailed)
+		return
+	}
+
+	slog.InfoContext(ctx, "Account deletion requested", "deletion_id", deletion.ID, "scheduled_for", deletion.ScheduledFor)
+
+	response := accountDeletionResponse(deletion)
+	response.ReceiptToken = receiptToken
+	c.JSON(http.StatusAccepted, models.ApiResponse{
+		Data:    response,
+		Message: i18n.Text(c.Request.Context(), i18n.MsgDeletionScheduled),
+	})
+}
+
+// GetAccountDeletion returns the user's pending deletion request.
+func (h *AuthHandler) GetAccountDeletion(c *gin.Context) {
+	deletion, ok := h.pendingDeletion(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: accountDeletionResponse(deletion),
+	})
+}
+
+// CancelAccountDeletion withdraws the pending request during the grace period.
+// Once a worker has claimed it the erasure is under way and the cancel is
+// refused.
+func (h *AuthHandler) CancelAccountDeletion(c *gin.Context) {
+	deletion, ok := h.pendingDeletion(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	cancelled, err := h.accountDeletions.Cancel(c.Request.Context(), deletion.ID, now)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error cancelling deletion request", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeDeletionCancelFailed)
+		return
+	}
+	if !cancelled {
+		apierror.Respond(c, http.StatusConflict, models.ErrCodeDeletionInProgress)
+		return
+	}
+	deletion.Status = models.AccountDeletionCancelled
+	deletion.CancelledAt = &now
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data:    accountDeletionResponse(deletion),
+		Message: i18n.Text(c.Request.Context(), i18n.MsgDeletionCancelled),
+	})
+}
+
+// GetDeletionReceipt is public: once the account is gone, the receipt token
+// handed out with the request is the only credential left.
+func (h *AuthHandler) GetDeletionReceipt(c *gin.Context) {
+	token := c.Query("token")
+	deletion, err := h.accountDeletions.Get(c.Request.Context(), c.Param("id"))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		slog.ErrorContext(c.Request.Context(), "Error loading deletion request", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	if deletion == nil || token == "" ||
+		subtle.ConstantTimeCompare([]byte(utils.HashToken(token)), []byte(deletion.ReceiptTokenHash)) != 1 {
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodeDeletionNotFound)
+		return
+	}
+
+	response := accountDeletionResponse(deletion)
+	response.Receipt = deletion.Receipt
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: response,
+	})
+}
+
+// RunAccountDeletionWorker erases the accounts whose grace period ended,
+// checking every interval until ctx is cancelled.
+func (h *AuthHandler) RunAccountDeletionWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A run in progress finishes even when shutdown starts, so no
+			// account is left half erased.
+			h.processDueDeletions(context.WithoutCancel(ctx))
+		}
+	}
+}
+
+// deletionLease bounds how long a worker holds a deletion request; a crashed
+// worker's request is picked up again once the lease expires.
+const deletionLease = 15 * time.Minute
+
+func (h *AuthHandler) processDueDeletions(ctx context.Context) {
+	due, err := h.accountDeletions.ListDue(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "Error listing due account deletions", "error", err)
+		return
+	}
+	for i := range due {
+		deletion := &due[i]
+		// Outra instância pode estar processando o mesmo pedido
+		now := time.Now()
+		claimed, err := h.accountDeletions.Claim(ctx, deletion.ID, now, now.Add(deletionLease))
+		if err != nil {
+			slog.ErrorContext(ctx, "Error claiming account deletion", "deletion_id", deletion.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := h.eraseAccount(ctx, deletion); err != nil {
+			// The request stays pending and is retried on the next run.
+			slog.ErrorContext(ctx, "Error erasing account", "user_id", deletion.UserID, "deletion_id", deletion.ID, "error", err)
+			deletion.LastError = err.Error()
+			if err := h.accountDeletions.Update(ctx, deletion); err != nil {
+				slog.ErrorContext(ctx, "Error saving deletion request", "deletion_id", deletion.ID, "error", err)
+			}
+		}
+	}
+}
+
+// eraseAccount runs the deletion: storage keys are collected before the rows
+// referencing them go away, and objects are only removed once the database
+// erasure succeeded.
+func (h *AuthHandler) eraseAccount(ctx context.Context, deletion *models.AccountDeletion) error {
+	userID := deletion.UserID
+
+	// Alguém pode ter entrado numa equipe do usuário durante a carência
+	conflicts, soloTeams, err := h.ownedTeams(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%d team(s) still need an ownership transfer", len(conflicts))
+	}
+
+	keys, err := h.userStorageKeys(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.revokeAllSessions(ctx, userID); err != nil {
+		return err
+	}
+	apiKeys, err := h.apiKeys.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, key := range apiKeys {
+		if err := h.apiKeys.Revoke(ctx, key.ID, now); err != nil {
+			return err
+		}
+	}
+
+	if err := h.repos.UserData.Erase(ctx, userID); err != nil {
+		return err
+	}
+	// No Supabase o cascade de auth.users já remove user_mfa e data_exports;
+	// os stores em memória dependem destas chamadas.
+	if err := h.mfa.Delete(ctx, userID); err != nil {
+		slog.ErrorContext(ctx, "Error deleting MFA settings of erased user", "user_id", userID, "error", err)
+	}
+	if err := h.deleteDataExports(ctx, userID); err != nil {
+		slog.ErrorContext(ctx, "Error deleting data exports of erased user", "user_id", userID, "error", err)
+	}
+
+	erased := append([]string(nil), erasedCategories...)
+	if len(soloTeams) > 0 {
+		if err := h.repos.Teams.Delete(ctx, soloTeams...); err != nil {
+			slog.ErrorContext(ctx, "Error deleting teams of erased user", "user_id", userID, "error", err)
+		} else {
+			erased = append(erased, "teams")
+		}
+	}
+
+	deleted, failed := 0, 0
+	if h.r2 == nil && len(keys) > 0 {
+		slog.WarnContext(ctx, "R2 not configured, objects of erased user left in storage", "user_id", userID, "objects", len(keys))
+		failed = len(keys)
+	}
+	for _, key := range keys {
+		if h.r2 == nil {
+			break
+		}
+		if err := h.r2.DeleteObject(ctx, key); err != nil {
+			slog.ErrorContext(ctx, "Error deleting R2 object", "object", key, "error", err)
+			failed++
+			continue
+		}
+		deleted++
+	}
+
+	completedAt := time.Now().UTC()
+	receipt, err := utils.SignDeletionReceipt(utils.DeletionReceipt{
+		RequestID:             deletion.ID,
+		UserID:                userID,
+		EmailSHA256:           deletion.EmailSHA256,
+		RequestedAt:           deletion.RequestedAt,
+		CompletedAt:           completedAt,
+		Erased:                erased,
+		StorageObjectsDeleted: deleted,
+		StorageObjectsFailed:  failed,
+	})
+	if err != nil {
+		// Sem comprovante o pedido continua pendente e a exclusão, que é
+		// idempotente, é refeita na próxima execução.
+		return fmt.Errorf("sign deletion receipt: %w", err)
+	}
+
+	deletion.Status = models.AccountDeletionCompleted
+	deletion.CompletedAt = &completedAt
+	deletion.Receipt = receipt
+	deletion.LastError = ""
+	if err := h.accountDeletions.Update(ctx, deletion); err != nil {
+		slog.ErrorContext(ctx, "Error completing deletion request", "deletion_id", deletion.ID, "error", err)
+	}
+
+	slog.InfoContext(ctx, "Account erased", "user_id", userID, "deletion_id", deletion.ID, "objects_removed", deleted)
+	return nil
+}
+
+// ownedTeams splits the teams the user owns into those that still have other
+// members (conflicts) and those the user is alone in, which are deleted with
+// the account.
+func (h *AuthHandler) ownedTeams(ctx context.Context, userID string) ([]models.TeamOwnershipConflict, []string, error) {
+	memberships, err := h.repos.TeamMembers.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var teamIDs []string
+	for _, m := range memberships {
+		if m.Role == models.TeamRoleOwner {
+			teamIDs = append(teamIDs, m.TeamID)
+		}
+	}
+	if len(teamIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	members, err := h.repos.TeamMembers.ListByTeams(ctx, teamIDs...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	others := make(map[string]int, len(teamIDs))
+	for _, m := range members {
+		if m.UserID != userID {
+			others[m.TeamID]++
+		}
+	}
+
+	var conflicts []models.TeamOwnershipConflict
+	var solo []string
+	for _, teamID := range teamIDs {
+		if others[teamID] > 0 {
+			conflicts = append(conflicts, models.TeamOwnershipConflict{TeamID: teamID, Members: others[teamID]})
+		} else {
+			solo = append(solo, teamID)
+		}
+	}
+	return conflicts, solo, nil
+}
+
+// userStorageKeys lists the R2 objects referenced by the user's petitions,
+// petition settings and data exports.
+func (h *AuthHandler) userStorageKeys(ctx context.Context, userID string) ([]string, error) {
+	var keys []string
+
+	petitions, err := h.repos.Petitions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(petitions) > 0 {
+		ids := make([]string, 0, len(petitions))
+		for _, p := range petitions {
+			ids = append(ids, p.ID)
+		}
+		documents, err := h.repos.Documents.ListDocuments(ctx, ids...)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range documents {
+			if d.R2Key != nil && *d.R2Key != "" {
+				keys = append(keys, *d.R2Key)
+			}
+		}
+		attachments, err := h.repos.Documents.ListAttachments(ctx, ids...)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range attachments {
+			if a.R2Key != nil && *a.R2Key != "" {
+				keys = append(keys, *a.R2Key)
+			}
+		}
+	}
+
+	settingsKeys, err := h.repos.UserData.SettingsStorageKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	keys = append(keys, settingsKeys...)
+
+	exports, err := h.dataExports.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range exports {
+		if e.ObjectKey != "" {
+			keys = append(keys, e.ObjectKey)
+		}
+	}
+
+	return keys, nil
+}
+
+// deleteDataExports removes the user's export records once their files are
+// collected for deletion.
+func (h *AuthHandler) deleteDataExports(ctx context.Context, userID string) error {
+	exports, err := h.dataExports.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, e := range exports {
+		if err := h.dataExports.Delete(ctx, e.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pendingDeletion loads the user's pending request, answering 404 when there
+// is none.
+func (h *AuthHandler) pendingDeletion(c *gin.Context) (*models.AccountDeletion, bool) {
+	deletion, err := h.accountDeletions.GetPending(c.Request.Context(), c.GetString("user_id"))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodeNoPendingDeletion)
+		return nil, false
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error loading deletion request", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return nil, false
+	}
+	return deletion, true
+}
+
+func accountDeletionResponse(deletion *models.AccountDeletion) models.AccountDeletionResponse {
+	return models.AccountDeletionResponse{
+		ID:           deletion.ID,
+		Status:       deletion.Status,
+		RequestedAt:  deletion.RequestedAt,
+		ScheduledFor: deletion.ScheduledFor,
+		CompletedAt:  deletion.CompletedAt,
+	}
+}
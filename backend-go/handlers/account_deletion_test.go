@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// deletionFixture wires AuthHandler to memory stores only, as with
+// DATA_STORE=memory and AUTH_STORE=memory.
+type deletionFixture struct {
+	h         *AuthHandler
+	repos     *store.Repositories
+	deletions *store.MemoryAccountDeletionStore
+	exports   *store.MemoryDataExportStore
+}
+
+func newDeletionFixture(t *testing.T) *deletionFixture {
+	t.Helper()
+	repos, err := store.NewMemoryRepositories(&store.Seed{
+		Users: []store.SeedUser{
+			{Profile: models.Profile{ID: "u1", Email: "gone@x.test", Name: "Gone"}, Password: "secret1"},
+			{Profile: models.Profile{ID: "u2", Email: "stays@x.test", Name: "Stays"}, Password: "secret1"},
+		},
+		Petitions: []models.Petition{
+			{ID: "p1", UserID: "u1", Title: "Erased"},
+			{ID: "p2", UserID: "u2", Title: "Kept"},
+		},
+		Documents: []models.PetitionDocument{
+			{ID: "d1", PetitionID: "p1", FileName: "a.pdf"},
+			{ID: "d2", PetitionID: "p2", FileName: "b.pdf"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &deletionFixture{
+		repos:     repos,
+		deletions: store.NewMemoryAccountDeletionStore(),
+		exports:   store.NewMemoryDataExportStore(),
+	}
+	f.h = NewAuthHandler(AuthDeps{
+		Repositories:     repos,
+		RefreshTokens:    store.NewMemoryRefreshTokenStore(),
+		Revocations:      store.NewMemoryRevocationList(),
+		MFA:              store.NewMemoryMFAStore(),
+		APIKeys:          store.NewMemoryAPIKeyStore(),
+		AccountDeletions: f.deletions,
+		DataExports:      f.exports,
+	})
+	return f
+}
+
+func (f *deletionFixture) request(t *testing.T, userID string, scheduledFor time.Time) *models.AccountDeletion {
+	t.Helper()
+	deletion := &models.AccountDeletion{
+		ID:           "del-" + userID,
+		UserID:       userID,
+		Status:       models.AccountDeletionPending,
+		RequestedAt:  scheduledFor.Add(-time.Hour),
+		ScheduledFor: scheduledFor,
+	}
+	if err := f.deletions.Create(context.Background(), deletion); err != nil {
+		t.Fatal(err)
+	}
+	return deletion
+}
+
+func TestDeletionWorkerErasesMemoryAccount(t *testing.T) {
+	f := newDeletionFixture(t)
+	ctx := context.Background()
+	f.request(t, "u1", time.Now().Add(-time.Minute))
+	if err := f.exports.Create(ctx, &models.DataExport{ID: "e1", UserID: "u1", Status: models.DataExportReady}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.h.processDueDeletions(ctx)
+
+	deletion, err := f.deletions.Get(ctx, "del-u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deletion.Status != models.AccountDeletionCompleted || deletion.Receipt == "" {
+		t.Fatalf("deletion = %s (last error %q), want completed with a receipt", deletion.Status, deletion.LastError)
+	}
+	if _, err := f.repos.Profiles.Get(ctx, "u1"); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("profile after erasure: %v, want not found", err)
+	}
+	if _, err := f.repos.Identity.SignIn(ctx, "gone@x.test", "secret1"); !errors.Is(err, store.ErrInvalidCredentials) {
+		t.Fatalf("sign in after erasure = %v, want invalid credentials", err)
+	}
+	if rows, err := f.repos.UserData.Rows(ctx, "petition_documents", "petition_id", "p1", "p2"); err != nil || len(rows) != 1 {
+		t.Fatalf("documents left = %d, %v; want only the other user's", len(rows), err)
+	}
+	if exports, err := f.exports.ListByUser(ctx, "u1"); err != nil || len(exports) != 0 {
+		t.Fatalf("exports left = %d, %v; want none", len(exports), err)
+	}
+	if _, err := f.repos.Profiles.Get(ctx, "u2"); err != nil {
+		t.Fatalf("other user's profile: %v", err)
+	}
+}
+
+func (f *deletionFixture) cancel(t *testing.T, userID string) (int, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.DELETE("/profile/deletion", func(c *gin.Context) { c.Set("user_id", userID) }, f.h.CancelAccountDeletion)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/profile/deletion", nil))
+	var resp struct {
+		Code string `json:"code"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp.Code
+}
+
+// A cancel that arrives after the worker claimed the request must not flip
+// it to cancelled halfway through the erasure.
+func TestCancelAccountDeletionRefusedWhileClaimed(t *testing.T) {
+	f := newDeletionFixture(t)
+	ctx := context.Background()
+	f.request(t, "u1", time.Now().Add(-time.Minute))
+	now := time.Now()
+	if claimed, err := f.deletions.Claim(ctx, "del-u1", now, now.Add(deletionLease)); err != nil || !claimed {
+		t.Fatalf("Claim = %v, %v", claimed, err)
+	}
+
+	if status, code := f.cancel(t, "u1"); status != http.StatusConflict || code != models.ErrCodeDeletionInProgress {
+		t.Fatalf("cancel = %d %s, want 409 %s", status, code, models.ErrCodeDeletionInProgress)
+	}
+	deletion, err := f.deletions.Get(ctx, "del-u1")
+	if err != nil || deletion.Status != models.AccountDeletionPending {
+		t.Fatalf("deletion after refused cancel = %+v, %v; want still pending", deletion, err)
+	}
+}
+
+func TestCancelAccountDeletionAfterExpiredLease(t *testing.T) {
+	f := newDeletionFixture(t)
+	ctx := context.Background()
+	f.request(t, "u1", time.Now().Add(time.Hour))
+	past := time.Now().Add(-time.Hour)
+	if claimed, err := f.deletions.Claim(ctx, "del-u1", past, past.Add(time.Minute)); err != nil || !claimed {
+		t.Fatalf("Claim = %v, %v", claimed, err)
+	}
+
+	if status, code := f.cancel(t, "u1"); status != http.StatusOK {
+		t.Fatalf("cancel = %d %s, want 200", status, code)
+	}
+	deletion, err := f.deletions.Get(ctx, "del-u1")
+	if err != nil || deletion.Status != models.AccountDeletionCancelled || deletion.CancelledAt == nil {
+		t.Fatalf("deletion after cancel = %+v, %v; want cancelled", deletion, err)
+	}
+}
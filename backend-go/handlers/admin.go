@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler serves the platform administration routes. Access control is
+// done by middleware.RequireAdmin on the route group.
+type AdminHandler struct {
+	repos *store.Repositories
+}
+
+func NewAdminHandler(repos *store.Repositories) *AdminHandler {
+	return &AdminHandler{repos: repos}
+}
+
+func (h *AdminHandler) GetStats(c *gin.Context) {
+	stats, err := h.repos.Stats.Admin(c.Request.Context())
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error loading admin stats", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeStatsFetchFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: stats,
+	})
+}
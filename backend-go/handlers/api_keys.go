@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/i18n"
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	apiKeyDefaultTTL = 90 * 24 * time.Hour
+	// apiKeyTouchInterval limits last_used_at writes to one per key per minute.
+	apiKeyTouchInterval = time.Minute
+)
+
+// ListAPIKeys returns the user's active keys, without their secrets.
+func (h *AuthHandler) ListAPIKeys(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	keys, err := h.apiKeys.ListByUser(c.Request.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error listing API keys", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeAPIKeysFetchFailed)
+		return
+	}
+
+	infos := make([]models.APIKeyInfo, 0, len(keys))
+	for _, key := range keys {
+		infos = append(infos, apiKeyInfo(&key))
+	}
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: infos,
+	})
+}
+
+// CreateAPIKey issues a new key. The key is only returned in this response;
+// afterwards only its prefix is known.
+func (h *AuthHandler) CreateAPIKey(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Invalid(c, err)
+		return
+	}
+
+	scopes, ok := normalizeScopes(req.Scopes)
+	if !ok {
+		apierror.Respond(c, http.StatusBadRequest, models.ErrCodeInvalidScopes, strings.Join(models.APIKeyScopes, ", "))
+		return
+	}
+
+	// Chaves de equipe só podem ser criadas por quem administra a equipe
+	if req.TeamID != "" {
+		teams, _ := c.Get("team_roles")
+		teamRoles, _ := teams.(map[string]string)
+		role := teamRoles[req.TeamID]
+		if role != models.TeamRoleOwner && role != models.TeamRoleGestor {
+			apierror.Respond(c, http.StatusForbidden, models.ErrCodeTeamKeyForbidden)
+			return
+		}
+	}
+
+	secret, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error generating API key", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	prefix, err := utils.GenerateOpaqueToken(4)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error generating API key", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	prefix = models.APIKeyPrefix + prefix
+	raw := prefix + "_" + secret
+
+	ttl := apiKeyDefaultTTL
+	if req.ExpiresInDays > 0 {
+		ttl = time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	}
+	now := time.Now().UTC()
+	key := &models.APIKey{
+		ID:        utils.GenerateID(),
+		UserID:    c.GetString("user_id"),
+		TeamID:    req.TeamID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   utils.HashToken(raw),
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := h.apiKeys.Create(c.Request.Context(), key); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error creating API key", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeAPIKeyCreateFailed)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Data: models.CreateAPIKeyResponse{
+			APIKeyInfo: apiKeyInfo(key),
+			Key:        raw,
+		},
+		Message: i18n.Text(c.Request.Context(), i18n.MsgAPIKeyCreated),
+	})
+}
+
+// UpdateAPIKey renames a key or replaces its scopes.
+func (h *AuthHandler) UpdateAPIKey(c *gin.Context) {
+	var req models.UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Invalid(c, err)
+		return
+	}
+
+	key, ok := h.ownedAPIKey(c)
+	if !ok {
+		return
+	}
+
+	if req.Name != "" {
+		key.Name = req.Name
+	}
+	if req.Scopes != nil {
+		scopes, ok := normalizeScopes(req.Scopes)
+		if !ok {
+			apierror.Respond(c, http.StatusBadRequest, models.ErrCodeInvalidScopes, strings.Join(models.APIKeyScopes, ", "))
+			return
+		}
+		key.Scopes = scopes
+	}
+
+	if err := h.apiKeys.Update(c.Request.Context(), key); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error updating API key", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeAPIKeyUpdateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: apiKeyInfo(key),
+	})
+}
+
+// DeleteAPIKey revokes a key. Requests using it fail from then on.
+func (h *AuthHandler) DeleteAPIKey(c *gin.Context) {
+	key, ok := h.ownedAPIKey(c)
+	if !ok {
+		return
+	}
+
+	if err := h.apiKeys.Revoke(c.Request.Context(), key.ID, time.Now().UTC()); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error revoking API key", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeAPIKeyRevokeFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Message: i18n.Text(c.Request.Context(), i18n.MsgAPIKeyRevoked),
+	})
+}
+
+// VerifyAPIKey implements middleware.APIKeyVerifier. Team roles are looked up
+// on every request, so a key stops working on a team its owner left.
+func (h *AuthHandler) VerifyAPIKey(ctx context.Context, raw string) (*utils.Identity, []string, error) {
+	key, err := h.apiKeys.GetByHash(ctx, utils.HashToken(raw))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if key.RevokedAt != nil || now.After(key.ExpiresAt) {
+		return nil, nil, nil
+	}
+
+	teams, err := h.teamRoles(ctx, key.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if key.TeamID != "" {
+		role, isMember := teams[key.TeamID]
+		if !isMember {
+			return nil, nil, nil
+		}
+		teams = map[string]string{key.TeamID: role}
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := h.apiKeys.TouchLastUsed(ctx, key.ID, now.UTC()); err != nil {
+			slog.ErrorContext(ctx, "Error updating API key last use", "error", err)
+		}
+	}
+
+	// Chaves nunca carregam privilégios de administrador
+	return &utils.Identity{
+		UserID:       key.UserID,
+		Teams:        teams,
+		APIKeyID:     key.ID,
+		APIKeyTeamID: key.TeamID,
+	}, key.Scopes, nil
+}
+
+// ownedAPIKey loads the active key named by the :id parameter, answering 404
+// when it does not exist or belongs to someone else.
+func (h *AuthHandler) ownedAPIKey(c *gin.Context) (*models.APIKey, bool) {
+	key, err := h.apiKeys.Get(c.Request.Context(), c.Param("id"))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		slog.ErrorContext(c.Request.Context(), "Error loading API key", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return nil, false
+	}
+	if key == nil || key.UserID != c.GetString("user_id") || key.RevokedAt != nil {
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodeAPIKeyNotFound)
+		return nil, false
+	}
+	return key, true
+}
+
+// normalizeScopes removes duplicates and rejects unknown or empty scope lists.
+func normalizeScopes(requested []string) ([]string, bool) {
+	seen := make(map[string]bool, len(requested))
+	var scopes []string
+	for _, scope := range requested {
+		if seen[scope] {
+			continue
+		}
+		known := false
+		for _, s := range models.APIKeyScopes {
+			if s == scope {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, false
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	return scopes, len(scopes) > 0
+}
+
+func apiKeyInfo(key *models.APIKey) models.APIKeyInfo {
+	return models.APIKeyInfo{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		TeamID:     key.TeamID,
+		Scopes:     key.Scopes,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+	}
+}
@@ -1,157 +1,171 @@
 package handlers
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/i18n"
+	"argumentum-backend/internal/metrics"
 	"argumentum-backend/models"
+	"argumentum-backend/r2"
+	"argumentum-backend/ratelimit"
+	"argumentum-backend/store"
 	"argumentum-backend/utils"
 	"context"
-	"crypto/rand"
-	"encoding/hex"
-	"log"
+	"errors"
+	"log/slog"
+	"math"
 	"net/http"
-	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/supabase-community/supabase-go"
+)
+
+const (
+	// refreshTokenTTL bounds a single refresh token; sessionTTL bounds the
+	// whole family, after which the user has to log in again.
+	refreshTokenTTL = 30 * 24 * time.Hour
+	sessionTTL      = 90 * 24 * time.Hour
 )
 
 type AuthHandler struct {
-	supabase *supabase.Client
+	repos         *store.Repositories
+	refreshTokens store.RefreshTokenStore
+	revocations   store.RevocationList
+	mfa           store.MFAStore
+	apiKeys       store.APIKeyStore
+	loginGuard    *ratelimit.Guard
+	resetGuard    *ratelimit.Guard
+
+	accountDeletions store.AccountDeletionStore
+	dataExports      store.DataExportStore
+	deletionGrace    time.Duration
+	r2               *r2.Client
 }
 
-func NewAuthHandler() *AuthHandler {
-	supabaseURL := os.Getenv("SUPABASE_URL")
-	supabaseKey := os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
-
-	if supabaseURL == "" {
-		supabaseURL = "https://mefgswdpeellvaggvttc.supabase.co"
-	}
-
-	client, err := supabase.NewClient(supabaseURL, supabaseKey, nil)
-	if err != nil {
-		log.Printf("Error creating Supabase client: %v", err)
-	}
+// AuthDeps groups the stores and guards shared between AuthHandler and the
+// middleware wired in main.go.
+type AuthDeps struct {
+	Repositories  *store.Repositories
+	RefreshTokens store.RefreshTokenStore
+	Revocations   store.RevocationList
+	MFA           store.MFAStore
+	APIKeys       store.APIKeyStore
+	LoginGuard    *ratelimit.Guard
+	ResetGuard    *ratelimit.Guard
+
+	AccountDeletions store.AccountDeletionStore
+	// DataExports lists the export files removed with an erased account.
+	DataExports store.DataExportStore
+	// DeletionGrace is how long a deletion request can be cancelled.
+	DeletionGrace time.Duration
+	// R2 removes the documents of erased accounts; nil when not configured.
+	R2 *r2.Client
+}
 
+func NewAuthHandler(deps AuthDeps) *AuthHandler {
 	return &AuthHandler{
-		supabase: client,
+		repos:         deps.Repositories,
+		refreshTokens: deps.RefreshTokens,
+		revocations:   deps.Revocations,
+		mfa:           deps.MFA,
+		apiKeys:       deps.APIKeys,
+		loginGuard:    deps.LoginGuard,
+		resetGuard:    deps.ResetGuard,
+
+		accountDeletions: deps.AccountDeletions,
+		dataExports:      deps.DataExports,
+		deletionGrace:    deps.DeletionGrace,
+		r2:               deps.R2,
 	}
 }
 
-// --- Aux Function: DoSupabaseREST ---
-func (h *AuthHandler) doSupabaseREST(ctx context.Context, method, path string, payload interface{}, result interface{}) error {
-	supabaseURL := os.Getenv("SUPABASE_URL")
-	supabaseKey := os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
-	if supabaseURL == "" {
-		supabaseURL = "https://mefgswdpeellvaggvttc.supabase.co"
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Invalid(c, err)
+		return
 	}
 
-	url := supabaseURL + path
-
-	var body io.Reader
-	if payload != nil {
-		buf, err := json.Marshal(payload)
-		if err != nil {
-			return err
-		}
-		body = bytes.NewBuffer(buf)
+	ctx := c.Request.Context()
+	attempt, throttled := h.throttled(c, h.loginGuard, req.Email)
+	if throttled {
+		metrics.Login(metrics.LoginThrottled)
+		return
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	userID, err := h.repos.Identity.SignIn(ctx, req.Email, req.Password)
 	if err != nil {
-		return err
+		slog.ErrorContext(ctx, "Login error", "error", err)
+		if isInvalidCredentials(err) {
+			metrics.Login(metrics.LoginFailure)
+		} else {
+			metrics.Login(metrics.LoginError)
+			h.forgive(c, h.loginGuard, attempt)
+		}
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidCredentials)
+		return
 	}
 
-	req.Header.Set("apikey", supabaseKey)
-	req.Header.Set("Authorization", "Bearer "+supabaseKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+	h.forgive(c, h.loginGuard, attempt)
+	if err := h.loginGuard.Reset(ctx, req.Email); err != nil {
+		slog.ErrorContext(ctx, "Error resetting login attempts", "error", err)
 	}
-	defer resp.Body.Close()
 
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	user, err := h.getUserProfile(ctx, userID)
+	if err != nil || user == nil {
+		slog.ErrorContext(ctx, "Error getting user profile", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeProfileFetchFailed)
+		return
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return &models.ApiError{
-			Status:  resp.StatusCode,
-			Message: string(respBytes),
+	if req.InviteID != "" {
+		rejection, err := h.checkInvite(ctx, req.InviteID, req.Email)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error checking team invite", "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+			return
+		}
+		if rejection != "" {
+			apierror.Respond(c, http.StatusBadRequest, rejection)
+			return
 		}
 	}
 
-	if result != nil {
-		return json.Unmarshal(respBytes, result)
-	}
-	return nil
-}
-
-func (h *AuthHandler) Login(c *gin.Context) {
-	var req models.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ApiResponse{
-			Error: "Dados inválidos: " + err.Error(),
-		})
+	// Contas com 2FA recebem apenas um token de verificação nesta etapa
+	mfaToken, err := h.mfaChallenge(ctx, user.ID, req.InviteID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error checking MFA", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
 		return
 	}
-
-	ctx := context.Background()
-	payload := map[string]interface{}{
-		"email":    req.Email,
-		"password": req.Password,
-	}
-	var authResp struct {
-		Session interface{} `json:"session"`
-		User    struct {
-			ID    string `json:"id"`
-			Email string `json:"email"`
-		} `json:"user"`
-	}
-	err := h.doSupabaseREST(ctx, "POST", "/auth/v1/token?grant_type=password", payload, &authResp)
-	if err != nil || authResp.User.ID == "" {
-		log.Printf("Login error: %v", err)
-		c.JSON(http.StatusUnauthorized, models.ApiResponse{
-			Error: "Email ou senha incorretos",
+	if mfaToken != "" {
+		metrics.Login(metrics.LoginMFARequired)
+		c.JSON(http.StatusOK, models.ApiResponse{
+			Data: models.MFAChallengeResponse{
+				MFARequired: true,
+				MFAToken:    mfaToken,
+			},
 		})
 		return
 	}
 
-	userID := authResp.User.ID
-
-	user, err := h.getUserProfile(userID)
-	if err != nil {
-		log.Printf("Error getting user profile: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Error: "Erro ao buscar perfil do usuário",
-		})
-		return
+	// Aceito antes de emitir o token para que a equipe já venha nas claims
+	if req.InviteID != "" {
+		if err := h.repos.Teams.AcceptInvite(ctx, req.InviteID, user.ID, req.Email); err != nil {
+			slog.ErrorContext(ctx, "Error accepting team invite", "invite_id", req.InviteID, "error", err)
+			respondInviteError(c, err)
+			return
+		}
 	}
 
-	token, err := utils.GenerateJWT(user.ID)
+	authResponse, err := h.startSession(c, user)
 	if err != nil {
-		log.Printf("Error generating JWT: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Error: "Erro interno do servidor",
-		})
+		slog.ErrorContext(ctx, "Error creating session", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
 		return
 	}
 
-	refreshToken := h.generateRefreshToken()
-
-	authResponse := models.AuthResponse{
-		User:         *user,
-		Token:        token,
-		RefreshToken: refreshToken,
-	}
-
+	metrics.Login(metrics.LoginSuccess)
 	c.JSON(http.StatusOK, models.ApiResponse{
 		Data: authResponse,
 	})
@@ -160,70 +174,72 @@ func (h *AuthHandler) Login(c *gin.Context) {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req models.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ApiResponse{
-			Error: "Dados inválidos: " + err.Error(),
-		})
+		apierror.Invalid(c, err)
 		return
 	}
 
 	if !req.TermsAccepted {
-		c.JSON(http.StatusBadRequest, models.ApiResponse{
-			Error: "É necessário aceitar os termos de uso",
-		})
+		apierror.Respond(c, http.StatusBadRequest, models.ErrCodeTermsNotAccepted)
 		return
 	}
 
-	ctx := context.Background()
-	payload := map[string]interface{}{
-		"email":    req.Email,
-		"password": req.Password,
-		"data": map[string]interface{}{
-			"full_name":         req.FullName,
-			"terms_accepted":    req.TermsAccepted,
-			"terms_accepted_at": time.Now().Format(time.RFC3339),
-		},
-	}
-	var signupResp struct {
-		User struct {
-			ID    string `json:"id"`
-			Email string `json:"email"`
-		} `json:"user"`
-	}
-	err := h.doSupabaseREST(ctx, "POST", "/auth/v1/signup", payload, &signupResp)
-	if err != nil || signupResp.User.ID == "" {
-		log.Printf("Registration error: %v", err)
-		c.JSON(http.StatusBadRequest, models.ApiResponse{
-			Error: "Erro ao criar conta: " + err.Error(),
-		})
+	ctx := c.Request.Context()
+
+	// Convites inválidos são recusados antes de a conta ser criada
+	if req.InviteID != "" {
+		rejection, err := h.checkInvite(ctx, req.InviteID, req.Email)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error checking team invite", "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+			return
+		}
+		if rejection != "" {
+			apierror.Respond(c, http.StatusBadRequest, rejection)
+			return
+		}
+	}
+
+	metadata := map[string]interface{}{
+		"full_name":         req.FullName,
+		"terms_accepted":    req.TermsAccepted,
+		"terms_accepted_at": time.Now().Format(time.RFC3339),
+	}
+	userID, err := h.repos.Identity.SignUp(ctx, req.Email, req.Password, metadata)
+	if err != nil {
+		slog.ErrorContext(ctx, "Registration error", "error", err)
+		apierror.Respond(c, http.StatusBadRequest, signUpErrorCode(err))
 		return
 	}
 
-	user, err := h.getUserProfile(signupResp.User.ID)
+	user, err := h.getUserProfile(ctx, userID)
 	if err != nil || user == nil {
-		log.Printf("Error getting user profile after registration: %v", err)
+		slog.ErrorContext(ctx, "Error getting user profile after registration", "error", err)
 		user = &models.User{
-			ID:      signupResp.User.ID,
+			ID:      userID,
 			Email:   req.Email,
 			Name:    req.FullName,
 			IsAdmin: false,
 		}
 	}
 
-	token, err := utils.GenerateJWT(user.ID)
-	if err != nil {
-		log.Printf("Error generating JWT: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Error: "Erro interno do servidor",
-		})
-		return
+	// Quem se cadastra por um convite entra na equipe ou fica sem conta: se
+	// o convite falhar agora, o cadastro é desfeito e o erro é devolvido.
+	if req.InviteID != "" {
+		if err := h.repos.Teams.AcceptInvite(ctx, req.InviteID, user.ID, req.Email); err != nil {
+			slog.ErrorContext(ctx, "Error accepting team invite", "invite_id", req.InviteID, "error", err)
+			if err := h.repos.Identity.DeleteUser(ctx, user.ID); err != nil {
+				slog.ErrorContext(ctx, "Error removing account after failed invite", "user_id", user.ID, "error", err)
+			}
+			respondInviteError(c, err)
+			return
+		}
 	}
 
-	refreshToken := h.generateRefreshToken()
-
-	authResponse := models.AuthResponse{
-		User:         *user,
-		Token:        token,
-		RefreshToken: refreshToken,
+	authResponse, err := h.startSession(c, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error creating session", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
 	}
 
 	c.JSON(http.StatusCreated, models.ApiResponse{
@@ -231,53 +247,147 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	})
 }
 
+// Logout revokes the access token used for the request and the refresh-token
+// family (session) it belongs to.
 func (h *AuthHandler) Logout(c *gin.Context) {
+	value, _ := c.Get("claims")
+	claims, ok := value.(*utils.Claims)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeUnauthenticated)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if err := h.revocations.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		slog.ErrorContext(ctx, "Error revoking access token", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeSessionEndFailed)
+		return
+	}
+
+	if claims.SessionID != "" {
+		if err := h.revokeSession(ctx, claims.SessionID); err != nil {
+			slog.ErrorContext(ctx, "Error revoking session", "session_id", claims.SessionID, "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeSessionEndFailed)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Message: i18n.Text(c.Request.Context(), i18n.MsgLoggedOut),
+	})
+}
+
+// LogoutAll ends every session of the user: all refresh-token families are
+// revoked and every access token issued until now is rejected.
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeUnauthenticated)
+		return
+	}
+
+	if _, err := h.revokeAllSessions(c.Request.Context(), userID); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error revoking sessions", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeSessionsEndFailed)
+		return
+	}
+
 	c.JSON(http.StatusOK, models.ApiResponse{
-		Message: "Logout realizado com sucesso",
+		Message: i18n.Text(c.Request.Context(), i18n.MsgAllSessionsEnded),
 	})
 }
 
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req models.RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ApiResponse{
-			Error: "Token de refresh inválido",
-		})
+		apierror.Respond(c, http.StatusBadRequest, models.ErrCodeInvalidRefreshToken)
 		return
 	}
 
-	if len(req.RefreshToken) < 10 {
-		c.JSON(http.StatusUnauthorized, models.ApiResponse{
-			Error: "Token de refresh inválido",
-		})
+	ctx := c.Request.Context()
+	now := time.Now().UTC()
+
+	current, err := h.refreshTokens.GetTokenByHash(ctx, utils.HashToken(req.RefreshToken))
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			slog.ErrorContext(ctx, "Error loading refresh token", "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+			return
+		}
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidRefreshToken)
 		return
 	}
 
-	userID := c.GetString("user_id")
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, models.ApiResponse{
-			Error: "Usuário não autenticado",
-		})
+	session, err := h.refreshTokens.GetSession(ctx, current.SessionID)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			slog.ErrorContext(ctx, "Error loading session", "session_id", current.SessionID, "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+			return
+		}
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidRefreshToken)
 		return
 	}
 
-	user, err := h.getUserProfile(userID)
+	if session.RevokedAt != nil || now.After(session.ExpiresAt) || now.After(current.ExpiresAt) {
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeSessionExpired)
+		return
+	}
+
+	// A token that was already exchanged is being replayed: someone else holds
+	// a copy, so the whole family is revoked.
+	fresh := false
+	if current.UsedAt == nil {
+		fresh, err = h.refreshTokens.MarkTokenUsed(ctx, current.ID, now)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error rotating refresh token", "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+			return
+		}
+	}
+	if !fresh {
+		slog.WarnContext(ctx, "Refresh token reuse detected, revoking family", "session_id", session.ID, "user_id", session.UserID)
+		if err := h.refreshTokens.RevokeSession(ctx, session.ID, now); err != nil {
+			slog.ErrorContext(ctx, "Error revoking session", "session_id", session.ID, "error", err)
+		}
+		if err := h.revocations.RevokeSession(ctx, session.ID, now.Add(utils.AccessTokenTTL)); err != nil {
+			slog.ErrorContext(ctx, "Error revoking session tokens", "session_id", session.ID, "error", err)
+		}
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeSessionRevoked)
+		return
+	}
+
+	if err := h.refreshTokens.TouchSession(ctx, session.ID, now, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		slog.ErrorContext(ctx, "Error updating session", "session_id", session.ID, "error", err)
+	}
+
+	user, err := h.getUserProfile(ctx, session.UserID)
+	if err != nil || user == nil {
+		slog.ErrorContext(ctx, "Error getting user profile", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeProfileFetchFailed)
+		return
+	}
+
+	identity, err := h.identityFor(ctx, user, session.ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Error: "Erro ao buscar perfil do usuário",
-		})
+		slog.ErrorContext(ctx, "Error loading team memberships", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
 		return
 	}
 
-	token, err := utils.GenerateJWT(user.ID)
+	token, err := utils.GenerateJWT(identity)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Error: "Erro interno do servidor",
-		})
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
 		return
 	}
 
-	refreshToken := h.generateRefreshToken()
+	refreshToken, err := h.issueRefreshToken(ctx, session)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error issuing refresh token", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
 
 	authResponse := models.AuthResponse{
 		User:         *user,
@@ -293,65 +403,212 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	var req models.ResetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ApiResponse{
-			Error: "Email inválido",
-		})
+		apierror.Invalid(c, err)
 		return
 	}
 
-	ctx := context.Background()
-	payload := map[string]interface{}{
-		"email": req.Email,
+	ctx := c.Request.Context()
+	// Todo pedido conta, não só os que falham
+	if _, throttled := h.throttled(c, h.resetGuard, req.Email); throttled {
+		return
 	}
-	var resp interface{}
-	err := h.doSupabaseREST(ctx, "POST", "/auth/v1/recover", payload, &resp)
-	if err != nil {
-		log.Printf("Error sending reset password email: %v", err)
+
+	if err := h.repos.Identity.SendRecovery(ctx, req.Email); err != nil {
+		slog.ErrorContext(ctx, "Error sending reset password email", "error", err)
 		c.JSON(http.StatusOK, models.ApiResponse{
-			Message: "Se o email existir, você receberá instruções para redefinir sua senha",
+			Message: i18n.Text(c.Request.Context(), i18n.MsgResetEmailSent),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, models.ApiResponse{
-		Message: "Se o email existir, você receberá instruções para redefinir sua senha",
+		Message: i18n.Text(c.Request.Context(), i18n.MsgResetEmailSent),
 	})
 }
 
+// JWKS publishes the public keys that verify our access tokens, so other
+// services (edge functions) never need the signing key.
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, utils.Keys().JWKS())
+}
+
 // --- Buscar perfil ---
 
-func (h *AuthHandler) getUserProfile(userID string) (*models.User, error) {
-	ctx := context.Background()
-	var profiles []struct {
-		ID      string `json:"id"`
-		Email   string `json:"email"`
-		Name    string `json:"name"`
-		IsAdmin bool   `json:"is_admin"`
-	}
-	query := map[string]string{"id": "eq." + userID}
-	queryString := "?"
-	for k, v := range query {
-		queryString += k + "=" + v + "&"
-	}
-	url := "/rest/v1/profiles" + queryString[:len(queryString)-1]
-	err := h.doSupabaseREST(ctx, "GET", url, nil, &profiles)
+func (h *AuthHandler) getUserProfile(ctx context.Context, userID string) (*models.User, error) {
+	profile, err := h.repos.Profiles.Get(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	if len(profiles) == 0 {
-		return nil, nil
+	return profile.User(), nil
+}
+
+// signUpErrorCode maps a GoTrue signup failure onto the code shown to the
+// user, instead of echoing GoTrue's own message.
+func signUpErrorCode(err error) string {
+	var apiErr *models.ApiError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case "user_already_exists", "email_exists":
+			return models.ErrCodeEmailAlreadyRegistered
+		case "weak_password":
+			return models.ErrCodeWeakPassword
+		}
+	}
+	return models.ErrCodeRegistrationFailed
+}
+
+// --- Proteção contra força bruta ---
+
+// throttled counts an attempt against the IP and account, or answers 429 when
+// they must wait before another one. Attempts are counted before the
+// credentials are checked, so a parallel burst is limited too; outcomes that
+// must not count give the attempt back with forgive. Limiter errors fail open
+// so an outage of a shared store does not lock everyone out.
+func (h *AuthHandler) throttled(c *gin.Context, guard *ratelimit.Guard, account string) (ratelimit.Attempt, bool) {
+	attempt, wait, err := guard.Acquire(c.Request.Context(), c.ClientIP(), account)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error checking attempt limiter", "error", err)
+		return attempt, false
+	}
+	if wait <= 0 {
+		return attempt, false
+	}
+
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	apierror.Respond(c, http.StatusTooManyRequests, models.ErrCodeTooManyAttempts, seconds)
+	return attempt, true
+}
+
+// forgive takes back an attempt counted by throttled: right credentials, or a
+// failure on our side that says nothing about them.
+func (h *AuthHandler) forgive(c *gin.Context, guard *ratelimit.Guard, attempt ratelimit.Attempt) {
+	if err := guard.Forgive(c.Request.Context(), attempt); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error forgiving attempt", "error", err)
 	}
-	profile := profiles[0]
-	return &models.User{
-		ID:      profile.ID,
-		Email:   profile.Email,
-		Name:    profile.Name,
-		IsAdmin: profile.IsAdmin,
+}
+
+// isInvalidCredentials tells a rejected password apart from GoTrue being
+// unavailable, which must not count as a failed attempt.
+func isInvalidCredentials(err error) bool {
+	return errors.Is(err, store.ErrInvalidCredentials)
+}
+
+// --- Sessões e refresh tokens ---
+
+// startSession opens a new token family for the device making the request and
+// returns the first access/refresh token pair of that session.
+func (h *AuthHandler) startSession(c *gin.Context, user *models.User) (*models.AuthResponse, error) {
+	return h.startSessionAfter(c, user, time.Time{})
+}
+
+// startSessionAfter starts a session whose access token is issued strictly
+// after cutoff, the instant returned by revokeAllSessions, so the revocation
+// never covers it.
+func (h *AuthHandler) startSessionAfter(c *gin.Context, user *models.User, cutoff time.Time) (*models.AuthResponse, error) {
+	now := time.Now().UTC()
+	session := &models.Session{
+		ID:         utils.GenerateID(),
+		UserID:     user.ID,
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(sessionTTL),
+	}
+	if err := h.refreshTokens.CreateSession(c.Request.Context(), session); err != nil {
+		return nil, err
+	}
+
+	identity, err := h.identityFor(c.Request.Context(), user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	identity.IssuedAfter = cutoff
+
+	token, err := utils.GenerateJWT(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := h.issueRefreshToken(c.Request.Context(), session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuthResponse{
+		User:         *user,
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// identityFor snapshots the user's admin flag and team roles into the claims
+// of the next access token.
+func (h *AuthHandler) identityFor(ctx context.Context, user *models.User, sessionID string) (utils.Identity, error) {
+	teams, err := h.teamRoles(ctx, user.ID)
+	if err != nil {
+		return utils.Identity{}, err
+	}
+
+	return utils.Identity{
+		UserID:    user.ID,
+		SessionID: sessionID,
+		IsAdmin:   user.IsAdmin,
+		Teams:     teams,
 	}, nil
 }
 
-func (h *AuthHandler) generateRefreshToken() string {
-	bytes := make([]byte, 32)
-	rand.Read(bytes)
-	return hex.EncodeToString(bytes)
+// teamRoles maps each team the user belongs to to the user's role in it.
+func (h *AuthHandler) teamRoles(ctx context.Context, userID string) (map[string]string, error) {
+	memberships, err := h.repos.TeamMembers.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make(map[string]string, len(memberships))
+	for _, m := range memberships {
+		teams[m.TeamID] = m.Role
+	}
+	return teams, nil
+}
+
+// revokeAllSessions ends every session of the user and rejects the access
+// tokens issued up to now, inclusive. It returns that cutoff.
+func (h *AuthHandler) revokeAllSessions(ctx context.Context, userID string) (time.Time, error) {
+	now := time.Now().UTC()
+	if err := h.refreshTokens.RevokeUserSessions(ctx, userID, now); err != nil {
+		return now, err
+	}
+	return now, h.revocations.RevokeUserTokens(ctx, userID, now)
+}
+
+func (h *AuthHandler) issueRefreshToken(ctx context.Context, session *models.Session) (string, error) {
+	raw, err := utils.GenerateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(refreshTokenTTL)
+	if session.ExpiresAt.Before(expiresAt) {
+		expiresAt = session.ExpiresAt
+	}
+
+	token := &models.RefreshToken{
+		ID:        utils.GenerateID(),
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		TokenHash: utils.HashToken(raw),
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	if err := h.refreshTokens.CreateToken(ctx, token); err != nil {
+		return "", err
+	}
+	return raw, nil
 }
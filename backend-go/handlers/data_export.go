@@ -0,0 +1,435 @@
+package handlers
+
+import (
+	"archive/zip"
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/i18n"
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// exportRetention is how long a finished export stays downloadable.
+	exportRetention = 7 * 24 * time.Hour
+	// exportLinkTTL bounds each download link handed out by the status endpoint.
+	exportLinkTTL = time.Hour
+	// exportObjectTTL bounds the signed R2 URL the download link redirects to.
+	exportObjectTTL = 5 * time.Minute
+	// exportTimeout bounds building one export.
+	exportTimeout = 30 * time.Minute
+	// maxConcurrentExports limits exports built at the same time.
+	maxConcurrentExports = 2
+)
+
+// exportFile is a document or attachment row, as far as the export needs it.
+type exportFile struct {
+	ID              string `json:"id"`
+	FileName        string `json:"file_name"`
+	R2Key           string `json:"r2_key"`
+	StoragePath     string `json:"storage_path"`
+	StorageProvider string `json:"storage_provider"`
+}
+
+// exportManifest is written as manifest.json at the root of the ZIP.
+type exportManifest struct {
+	ExportID     string         `json:"export_id"`
+	UserID       string         `json:"user_id"`
+	GeneratedAt  time.Time      `json:"generated_at"`
+	Records      map[string]int `json:"records"`
+	Files        int            `json:"files"`
+	MissingFiles []string       `json:"missing_files,omitempty"`
+}
+
+// RequestDataExport starts building the user's data export (LGPD
+// portability). Only one export can be in progress per user.
+func (h *ProfileHandler) RequestDataExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	ctx := c.Request.Context()
+
+	// Os ZIPs ficam no R2; sem ele não há onde guardar a exportação
+	if h.r2 == nil {
+		apierror.Respond(c, http.StatusServiceUnavailable, models.ErrCodeExportUnavailable)
+		return
+	}
+
+	active, err := h.exports.GetActive(ctx, userID)
+	if err == nil && time.Since(active.CreatedAt) > exportTimeout {
+		// Interrompida por um reinício do servidor: libera uma nova exportação
+		active.Status = models.DataExportFailed
+		active.Error = "interrupted"
+		if err := h.exports.Update(ctx, active); err != nil {
+			slog.ErrorContext(ctx, "Error updating data export", "export_id", active.ID, "error", err)
+		}
+		err = store.ErrNotFound
+	}
+	if err == nil {
+		resp := apierror.New(c, models.ErrCodeExportInProgress)
+		resp.Data = dataExportResponse(active, "")
+		c.JSON(http.StatusConflict, resp)
+		return
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		slog.ErrorContext(ctx, "Error loading data exports", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	now := time.Now().UTC()
+	export := &models.DataExport{
+		ID:        utils.GenerateID(),
+		UserID:    userID,
+		Status:    models.DataExportPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(exportRetention),
+	}
+	if err := h.exports.Create(ctx, export); err != nil {
+		slog.ErrorContext(ctx, "Error creating data export", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeExportStartFailed)
+		return
+	}
+
+	h.builds.Add(1)
+	go h.buildExport(export)
+
+	c.JSON(http.StatusAccepted, models.ApiResponse{
+		Data:    dataExportResponse(export, ""),
+		Message: i18n.Text(c.Request.Context(), i18n.MsgExportStarted),
+	})
+}
+
+// GetDataExport returns the export status and, once it is ready, a download
+// link valid for exportLinkTTL.
+func (h *ProfileHandler) GetDataExport(c *gin.Context) {
+	export, err := h.exports.Get(c.Request.Context(), c.Param("id"))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		slog.ErrorContext(c.Request.Context(), "Error loading data export", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	if export == nil || export.UserID != c.GetString("user_id") {
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodeExportNotFound)
+		return
+	}
+
+	downloadURL := ""
+	if export.Status == models.DataExportReady {
+		token, err := utils.GenerateDownloadToken("export:"+export.ID, exportLinkTTL)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "Error generating download token", "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+			return
+		}
+		downloadURL = "/exports/" + export.ID + "/download?token=" + url.QueryEscape(token)
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: dataExportResponse(export, downloadURL),
+	})
+}
+
+// DownloadDataExport redirects to a short-lived signed R2 URL for the ZIP. It
+// is public so the link works from a browser; the signed token in the link is
+// the credential.
+func (h *ProfileHandler) DownloadDataExport(c *gin.Context) {
+	id := c.Param("id")
+	if err := utils.ValidateDownloadToken(c.Query("token"), "export:"+id); err != nil {
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidDownloadLink)
+		return
+	}
+
+	export, err := h.exports.Get(c.Request.Context(), id)
+	if err != nil || export.Status != models.DataExportReady || time.Now().After(export.ExpiresAt) ||
+		export.ObjectKey == "" || h.r2 == nil {
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodeExportNotFound)
+		return
+	}
+
+	fileName := "argumentum-dados-" + export.CreatedAt.Format("2006-01-02") + ".zip"
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, h.r2.PresignGet(export.ObjectKey, exportObjectTTL, fileName))
+}
+
+// RunExportCleanup removes expired exports, files included, every interval
+// until ctx is cancelled.
+func (h *ProfileHandler) RunExportCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.removeExpiredExports(context.WithoutCancel(ctx))
+		}
+	}
+}
+
+func (h *ProfileHandler) removeExpiredExports(ctx context.Context) {
+	expired, err := h.exports.ListExpired(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "Error listing expired data exports", "error", err)
+		return
+	}
+	for _, export := range expired {
+		if export.ObjectKey != "" && h.r2 != nil {
+			if err := h.r2.DeleteObject(ctx, export.ObjectKey); err != nil {
+				slog.ErrorContext(ctx, "Error removing export file", "export_id", export.ID, "error", err)
+				continue
+			}
+		}
+		if err := h.exports.Delete(ctx, export.ID); err != nil {
+			slog.ErrorContext(ctx, "Error deleting data export", "export_id", export.ID, "error", err)
+		}
+	}
+}
+
+// WaitExports waits for the exports being built. When ctx ends first, the
+// builds are cancelled and recorded as failed so users can ask again.
+func (h *ProfileHandler) WaitExports(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.builds.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		h.cancelBuilds()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (h *ProfileHandler) buildExport(export *models.DataExport) {
+	defer h.builds.Done()
+	h.exportSlots <- struct{}{}
+	defer func() { <-h.exportSlots }()
+
+	ctx, cancel := context.WithTimeout(h.buildCtx, exportTimeout)
+	defer cancel()
+
+	export.Status = models.DataExportProcessing
+	if err := h.exports.Update(ctx, export); err != nil {
+		slog.ErrorContext(ctx, "Error updating data export", "export_id", export.ID, "error", err)
+	}
+
+	key, size, err := h.writeExport(ctx, export)
+	now := time.Now().UTC()
+	export.CompletedAt = &now
+	if err != nil {
+		slog.ErrorContext(ctx, "Error building data export", "export_id", export.ID, "error", err)
+		export.Status = models.DataExportFailed
+		export.Error = err.Error()
+	} else {
+		slog.InfoContext(ctx, "Data export ready", "export_id", export.ID, "bytes", size)
+		export.Status = models.DataExportReady
+		export.ObjectKey = key
+		export.SizeBytes = size
+	}
+	// ctx may have timed out by now; the outcome must still be recorded.
+	if err := h.exports.Update(context.Background(), export); err != nil {
+		slog.ErrorContext(ctx, "Error updating data export", "export_id", export.ID, "error", err)
+	}
+}
+
+// writeExport builds the ZIP in a temporary file and uploads it to R2 once
+// complete, returning the object key. No instance keeps the file: any of
+// them can serve the download.
+func (h *ProfileHandler) writeExport(ctx context.Context, export *models.DataExport) (string, int64, error) {
+	if h.r2 == nil {
+		return "", 0, errors.New("r2 not configured")
+	}
+	tmp, err := os.CreateTemp("", "argumentum-export-"+export.ID+"-*.zip")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := zip.NewWriter(tmp)
+	if err := h.writeExportContents(ctx, zw, export); err != nil {
+		return "", 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return "", 0, err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+
+	key := "exports/" + export.UserID + "/" + export.ID + ".zip"
+	if err := h.r2.PutObject(ctx, key, tmp, size, "application/zip"); err != nil {
+		return "", 0, fmt.Errorf("upload export: %w", err)
+	}
+	return key, size, nil
+}
+
+func (h *ProfileHandler) writeExportContents(ctx context.Context, zw *zip.Writer, export *models.DataExport) error {
+	userID := export.UserID
+	manifest := exportManifest{
+		ExportID:    export.ID,
+		UserID:      export.UserID,
+		GeneratedAt: time.Now().UTC(),
+		Records:     make(map[string]int),
+	}
+
+	datasets := []struct {
+		name, table, column string
+	}{
+		{"profile", "profiles", "id"},
+		{"petitions", "petitions", "user_id"},
+		{"petition_comments", "petition_comments", "author_id"},
+		{"petition_settings", "petition_settings", "user_id"},
+		{"token_transactions", "token_transactions", "user_id"},
+		{"team_memberships", "team_members", "user_id"},
+	}
+	var petitionIDs []string
+	for _, d := range datasets {
+		rows, err := h.writeDataset(ctx, zw, d.name, d.table, d.column, []string{userID}, &manifest)
+		if err != nil {
+			return err
+		}
+		if d.name == "petitions" {
+			for _, row := range rows {
+				var petition struct {
+					ID string `json:"id"`
+				}
+				if err := json.Unmarshal(row, &petition); err == nil {
+					petitionIDs = append(petitionIDs, petition.ID)
+				}
+			}
+		}
+	}
+
+	// Metadados e arquivos originais dos documentos e anexos das petições
+	if len(petitionIDs) > 0 {
+		for _, table := range []string{"petition_documents", "petition_attachments"} {
+			rows, err := h.writeDataset(ctx, zw, table, table, "petition_id", petitionIDs, &manifest)
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				var file exportFile
+				if err := json.Unmarshal(row, &file); err != nil {
+					continue
+				}
+				name := "files/" + table + "/" + file.ID + "-" + sanitizeFileName(file.FileName)
+				if err := h.writeExportFile(ctx, zw, name, file); err != nil {
+					slog.ErrorContext(ctx, "Error adding file to data export", "file", name, "export_id", export.ID, "error", err)
+					manifest.MissingFiles = append(manifest.MissingFiles, name)
+					continue
+				}
+				manifest.Files++
+			}
+		}
+	}
+
+	w, err := zw.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(manifest)
+}
+
+// writeDataset stores the rows of table whose column is one of values as
+// <name>.json.
+func (h *ProfileHandler) writeDataset(ctx context.Context, zw *zip.Writer, name, table, column string, values []string, manifest *exportManifest) ([]json.RawMessage, error) {
+	rows, err := h.repos.UserData.Rows(ctx, table, column, values...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	manifest.Records[name] = len(rows)
+
+	if rows == nil {
+		rows = []json.RawMessage{}
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, data, "", "  "); err != nil {
+		return nil, err
+	}
+	w, err := zw.Create(name + ".json")
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Write(pretty.Bytes())
+	return rows, err
+}
+
+// writeExportFile copies an original file, from R2 or Supabase Storage
+// depending on where it was uploaded.
+func (h *ProfileHandler) writeExportFile(ctx context.Context, zw *zip.Writer, name string, file exportFile) error {
+	var body io.ReadCloser
+	var err error
+	switch {
+	case file.R2Key != "" && h.r2 != nil:
+		body, err = h.r2.GetObject(ctx, file.R2Key)
+	case file.StoragePath != "" && file.StorageProvider == "supabase":
+		body, err = h.repos.UserData.StorageObject(ctx, "petition-assets", file.StoragePath)
+	default:
+		return errors.New("file location unknown or storage not configured")
+	}
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, body)
+	return err
+}
+
+func dataExportResponse(export *models.DataExport, downloadURL string) models.DataExportResponse {
+	return models.DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		SizeBytes:   export.SizeBytes,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+		DownloadURL: downloadURL,
+	}
+}
+
+// sanitizeFileName keeps user-supplied names from escaping their folder in
+// the ZIP.
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == ".." {
+		return "arquivo"
+	}
+	return name
+}
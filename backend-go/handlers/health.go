@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"argumentum-backend/internal/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	checker  *health.Checker
+	draining atomic.Bool
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Live answers as long as the process can serve requests.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "message": "Argumentum Backend Go is running"})
+}
+
+// Ready reports whether GoTrue, PostgREST and Storage are reachable, and
+// turns unready as soon as shutdown starts so the load balancer stops
+// sending traffic before the server closes.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+
+	checks, ready := h.checker.Run(c.Request.Context())
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready", "checks": checks})
+}
+
+// Drain marks the server as shutting down.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inviteRejections are the exceptions raised by accept_team_invite. They
+// double as the error codes shown to the user.
+var inviteRejections = map[string]bool{
+	models.ErrCodeInviteNotFound:      true,
+	models.ErrCodeInviteNotPending:    true,
+	models.ErrCodeInviteExpired:       true,
+	models.ErrCodeInviteEmailMismatch: true,
+}
+
+// checkInvite validates an invite before the account is created or the login
+// completes. It returns the rejection code, or "" when the invite can be
+// accepted by email.
+func (h *AuthHandler) checkInvite(ctx context.Context, inviteID, email string) (string, error) {
+	invite, err := h.repos.Teams.GetInvite(ctx, inviteID)
+	if errors.Is(err, store.ErrNotFound) {
+		return models.ErrCodeInviteNotFound, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case invite.Status != models.InviteStatusPending:
+		return models.ErrCodeInviteNotPending, nil
+	case invite.ExpiresAt.Before(time.Now()):
+		return models.ErrCodeInviteExpired, nil
+	case !strings.EqualFold(invite.Email, email):
+		return models.ErrCodeInviteEmailMismatch, nil
+	}
+	return "", nil
+}
+
+// respondInviteError answers a failed acceptInvite, telling an invite that
+// was rejected apart from an internal failure.
+func respondInviteError(c *gin.Context, err error) {
+	var rejected *store.InviteRejectedError
+	if errors.As(err, &rejected) {
+		if inviteRejections[rejected.Code] {
+			apierror.Respond(c, http.StatusConflict, rejected.Code)
+			return
+		}
+	}
+	apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInviteAcceptFailed)
+}
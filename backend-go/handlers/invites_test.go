@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// racedInvites passes the invite check but loses the acceptance, as when
+// another request accepts the invite in between.
+type racedInvites struct {
+	store.TeamStore
+}
+
+func (racedInvites) AcceptInvite(ctx context.Context, inviteID, userID, email string) error {
+	return &store.InviteRejectedError{Code: models.ErrCodeInviteNotPending}
+}
+
+func TestRegisterUndoesAccountWhenInviteFails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Now().UTC()
+	repos, err := store.NewMemoryRepositories(&store.Seed{
+		Teams: []models.Team{{ID: "t1", CreatedAt: now, UpdatedAt: now}},
+		TeamInvites: []models.TeamInvite{{
+			ID: "inv1", TeamID: "t1", Email: "new@x.test", Role: models.TeamRoleOperador,
+			Status: models.InviteStatusPending, ExpiresAt: now.Add(time.Hour), CreatedAt: now,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos.Teams = racedInvites{repos.Teams}
+	h := NewAuthHandler(AuthDeps{
+		Repositories:  repos,
+		RefreshTokens: store.NewMemoryRefreshTokenStore(),
+		Revocations:   store.NewMemoryRevocationList(),
+	})
+	r := gin.New()
+	r.POST("/auth/register", h.Register)
+
+	body := `{"email":"new@x.test","password":"secret1","fullName":"New","termsAccepted":true,"inviteId":"inv1"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid body %s: %v", w.Body, err)
+	}
+	if w.Code != http.StatusConflict || resp.Code != models.ErrCodeInviteNotPending {
+		t.Fatalf("register = %d %s, want 409 %s", w.Code, resp.Code, models.ErrCodeInviteNotPending)
+	}
+	if _, err := repos.Identity.SignIn(context.Background(), "new@x.test", "secret1"); !errors.Is(err, store.ErrInvalidCredentials) {
+		t.Fatalf("sign in after failed invite = %v, want the account removed", err)
+	}
+}
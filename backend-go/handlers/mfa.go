@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/i18n"
+	"argumentum-backend/internal/metrics"
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	mfaIssuer         = "Argumentum"
+	recoveryCodeCount = 10
+)
+
+// EnrollMFA starts a TOTP enrollment. The secret only becomes active after
+// VerifyMFA confirms the user can generate codes with it.
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	userID := c.GetString("user_id")
+	ctx := c.Request.Context()
+
+	existing, err := h.mfa.Get(ctx, userID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		slog.ErrorContext(ctx, "Error loading MFA config", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	if existing != nil && existing.Enabled {
+		apierror.Respond(c, http.StatusConflict, models.ErrCodeMFAAlreadyEnabled)
+		return
+	}
+
+	user, err := h.getUserProfile(ctx, userID)
+	if err != nil || user == nil {
+		slog.ErrorContext(ctx, "Error getting user profile", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeProfileFetchFailed)
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		slog.ErrorContext(ctx, "Error generating TOTP secret", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	encrypted, err := utils.EncryptSecret(secret)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encrypting TOTP secret", "error", err)
+		status := http.StatusInternalServerError
+		if errors.Is(err, utils.ErrSecretKeyMissing) {
+			status = http.StatusServiceUnavailable
+		}
+		apierror.Respond(c, status, models.ErrCodeMFAUnavailable)
+		return
+	}
+
+	config := &models.MFAConfig{
+		UserID:          userID,
+		SecretEncrypted: encrypted,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if err := h.mfa.Save(ctx, config); err != nil {
+		slog.ErrorContext(ctx, "Error saving MFA config", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: models.MFAEnrollResponse{
+			Secret:     secret,
+			OtpauthURI: utils.TOTPURI(secret, user.Email, mfaIssuer),
+		},
+	})
+}
+
+// VerifyMFA activates a pending enrollment and returns the recovery codes,
+// which are shown only this once.
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, models.ErrCodeInvalidMFACode)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	ctx := c.Request.Context()
+
+	config, err := h.mfa.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			apierror.Respond(c, http.StatusBadRequest, models.ErrCodeMFAEnrollmentNotFound)
+			return
+		}
+		slog.ErrorContext(ctx, "Error loading MFA config", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	if config.Enabled {
+		apierror.Respond(c, http.StatusConflict, models.ErrCodeMFAAlreadyEnabled)
+		return
+	}
+
+	attempt, throttled := h.throttled(c, h.loginGuard, mfaAccount(userID))
+	if throttled {
+		return
+	}
+	prev := snapshotMFA(config)
+	if !h.checkTOTP(config, req.Code) {
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidMFACode)
+		return
+	}
+	h.forgive(c, h.loginGuard, attempt)
+
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error generating recovery codes", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	now := time.Now().UTC()
+	config.Enabled = true
+	config.EnabledAt = &now
+	config.RecoveryCodeHashes = make([]string, len(codes))
+	for i, code := range codes {
+		config.RecoveryCodeHashes[i] = utils.HashToken(code)
+	}
+	if !h.saveConsumedMFA(c, config, prev) {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data:    models.MFARecoveryCodesResponse{RecoveryCodes: codes},
+		Message: i18n.Text(c.Request.Context(), i18n.MsgMFAEnabled),
+	})
+}
+
+// DisableMFA turns 2FA off; it requires a current TOTP or recovery code.
+func (h *AuthHandler) DisableMFA(c *gin.Context) {
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, models.ErrCodeInvalidMFACode)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	ctx := c.Request.Context()
+
+	config, err := h.mfa.Get(ctx, userID)
+	if err != nil || !config.Enabled {
+		apierror.Respond(c, http.StatusBadRequest, models.ErrCodeMFANotEnabled)
+		return
+	}
+
+	attempt, throttled := h.throttled(c, h.loginGuard, mfaAccount(userID))
+	if throttled {
+		return
+	}
+	prev := snapshotMFA(config)
+	if !h.checkTOTP(config, req.Code) && !consumeRecoveryCode(config, req.Code) {
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidMFACode)
+		return
+	}
+	h.forgive(c, h.loginGuard, attempt)
+	// Consumir o código antes de apagar impede que ele seja reaproveitado
+	if !h.saveConsumedMFA(c, config, prev) {
+		return
+	}
+
+	if err := h.mfa.Delete(ctx, userID); err != nil {
+		slog.ErrorContext(ctx, "Error deleting MFA config", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Message: i18n.Text(c.Request.Context(), i18n.MsgMFADisabled),
+	})
+}
+
+// LoginMFA is the second login step: it exchanges the mfaToken returned by
+// Login plus a TOTP (or recovery) code for a full session.
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var req models.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Invalid(c, err)
+		return
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		apierror.Required(c, "code")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	claims, err := utils.ValidateMFAPendingJWT(req.MFAToken)
+	if err != nil {
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidMFAToken)
+		return
+	}
+	revoked, err := h.revocations.IsRevoked(ctx, claims.ID, "", claims.UserID, claims.IssuedAt.Time)
+	if err != nil || revoked {
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidMFAToken)
+		return
+	}
+
+	// Códigos errados contam contra a conta, como senhas erradas
+	account := mfaAccount(claims.UserID)
+	attempt, throttled := h.throttled(c, h.loginGuard, account)
+	if throttled {
+		metrics.Login(metrics.LoginThrottled)
+		return
+	}
+
+	config, err := h.mfa.Get(ctx, claims.UserID)
+	if err != nil || !config.Enabled {
+		slog.ErrorContext(ctx, "Error loading MFA config for pending login", "error", err)
+		h.forgive(c, h.loginGuard, attempt)
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidMFAToken)
+		return
+	}
+	prev := snapshotMFA(config)
+
+	if req.Code != "" {
+		if !h.checkTOTP(config, req.Code) {
+			metrics.Login(metrics.LoginFailure)
+			apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidMFACode)
+			return
+		}
+	} else if !consumeRecoveryCode(config, req.RecoveryCode) {
+		metrics.Login(metrics.LoginFailure)
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidRecoveryCode)
+		return
+	}
+	h.forgive(c, h.loginGuard, attempt)
+	if err := h.loginGuard.Reset(ctx, account); err != nil {
+		slog.ErrorContext(ctx, "Error resetting MFA attempts", "error", err)
+	}
+
+	// Persist the consumed step / recovery code and burn the pending token.
+	if !h.saveConsumedMFA(c, config, prev) {
+		return
+	}
+	if err := h.revocations.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		slog.ErrorContext(ctx, "Error revoking MFA token", "error", err)
+	}
+
+	user, err := h.getUserProfile(ctx, claims.UserID)
+	if err != nil || user == nil {
+		slog.ErrorContext(ctx, "Error getting user profile", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeProfileFetchFailed)
+		return
+	}
+
+	if claims.InviteID != "" {
+		if err := h.repos.Teams.AcceptInvite(ctx, claims.InviteID, user.ID, user.Email); err != nil {
+			slog.ErrorContext(ctx, "Error accepting team invite", "invite_id", claims.InviteID, "error", err)
+			respondInviteError(c, err)
+			return
+		}
+	}
+
+	authResponse, err := h.startSession(c, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error creating session", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	metrics.Login(metrics.LoginSuccess)
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: authResponse,
+	})
+}
+
+// mfaChallenge returns the pending token when the user has 2FA enabled, or
+// "" when the login can complete right away.
+func (h *AuthHandler) mfaChallenge(ctx context.Context, userID, inviteID string) (string, error) {
+	config, err := h.mfa.Get(ctx, userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !config.Enabled {
+		return "", nil
+	}
+	return utils.GenerateMFAPendingJWT(userID, inviteID)
+}
+
+// mfaAccount is the attempt limiter key for TOTP and recovery codes. Setup,
+// disable and login share it, so none of them can be used to guess codes
+// beyond the limit of the others.
+func mfaAccount(userID string) string {
+	return "mfa:" + userID
+}
+
+// snapshotMFA copies the enrollment as it was read, before checkTOTP or
+// consumeRecoveryCode change it.
+func snapshotMFA(config *models.MFAConfig) models.MFAConfig {
+	prev := *config
+	prev.RecoveryCodeHashes = append([]string(nil), config.RecoveryCodeHashes...)
+	return prev
+}
+
+// saveConsumedMFA persists a consumed code only if no other request changed
+// the enrollment since prev was read; otherwise two requests could both
+// accept the same code. It answers the request and returns false on failure.
+func (h *AuthHandler) saveConsumedMFA(c *gin.Context, config *models.MFAConfig, prev models.MFAConfig) bool {
+	ctx := c.Request.Context()
+	saved, err := h.mfa.Update(ctx, config, &prev)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error saving MFA config", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return false
+	}
+	if !saved {
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeInvalidMFACode)
+		return false
+	}
+	return true
+}
+
+// checkTOTP validates the code and advances LastUsedStep so a code cannot be
+// replayed; callers must save the config afterwards with saveConsumedMFA.
+func (h *AuthHandler) checkTOTP(config *models.MFAConfig, code string) bool {
+	secret, err := utils.DecryptSecret(config.SecretEncrypted)
+	if err != nil {
+		slog.Error("Error decrypting TOTP secret", "error", err)
+		return false
+	}
+	step, ok := utils.ValidateTOTP(secret, strings.TrimSpace(code), time.Now())
+	if !ok || step <= config.LastUsedStep {
+		return false
+	}
+	config.LastUsedStep = step
+	return true
+}
+
+// consumeRecoveryCode removes the code from the config when it matches;
+// callers must save the config afterwards with saveConsumedMFA.
+func consumeRecoveryCode(config *models.MFAConfig, code string) bool {
+	hash := []byte(utils.HashToken(utils.NormalizeRecoveryCode(code)))
+	for i, h := range config.RecoveryCodeHashes {
+		if subtle.ConstantTimeCompare([]byte(h), hash) == 1 {
+			config.RecoveryCodeHashes = append(config.RecoveryCodeHashes[:i], config.RecoveryCodeHashes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
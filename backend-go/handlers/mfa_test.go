@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"testing"
+
+	"argumentum-backend/models"
+	"argumentum-backend/utils"
+)
+
+// Recovery codes are issued as xxxxx-xxxxx but often typed or pasted
+// differently; every variant of the same code is accepted once.
+func TestConsumeRecoveryCodeNormalizesInput(t *testing.T) {
+	const issued = "a1b2c-3d4e5"
+	cases := []struct {
+		name, input string
+		want        bool
+	}{
+		{"as issued", issued, true},
+		{"uppercase", "A1B2C-3D4E5", true},
+		{"no separator", "a1b2c3d4e5", true},
+		{"space instead of dash", "a1b2c 3d4e5", true},
+		{"surrounding whitespace", "  a1b2c-3d4e5\n", true},
+		{"other separators", "a1b2c–3d4e5", true},
+		{"wrong code", "a1b2c-3d4e6", false},
+		{"too short", "a1b2c-3d4e", false},
+		{"empty", "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &models.MFAConfig{RecoveryCodeHashes: []string{utils.HashToken("other-code0"), utils.HashToken(issued)}}
+			if got := consumeRecoveryCode(config, tc.input); got != tc.want {
+				t.Fatalf("consumeRecoveryCode(%q) = %v, want %v", tc.input, got, tc.want)
+			}
+			if tc.want {
+				if len(config.RecoveryCodeHashes) != 1 || consumeRecoveryCode(config, tc.input) {
+					t.Fatalf("code %q not consumed", tc.input)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/i18n"
+	"argumentum-backend/models"
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfirmResetPassword sets a new password using the recovery token sent by
+// GoTrue, then ends every existing session of the account.
+func (h *AuthHandler) ConfirmResetPassword(c *gin.Context) {
+	var req models.ConfirmResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Invalid(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// O código de 6 dígitos é adivinhável: falhas contam contra o email/IP
+	account := "confirm:" + req.Email
+	attempt, throttled := h.throttled(c, h.resetGuard, account)
+	if throttled {
+		return
+	}
+
+	userID, err := h.repos.Identity.VerifyRecovery(ctx, req.Email, req.Token)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error verifying recovery token", "error", err)
+		apierror.Respond(c, http.StatusBadRequest, models.ErrCodeInvalidRecoveryLink)
+		return
+	}
+	h.forgive(c, h.resetGuard, attempt)
+
+	if err := h.repos.Identity.SetPassword(ctx, userID, req.Password); err != nil {
+		slog.ErrorContext(ctx, "Error updating password", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodePasswordResetFailed)
+		return
+	}
+
+	// Quem pediu o reset pode estar fugindo de uma sessão roubada: sem
+	// encerrá-las, o reset não cumpriu o que promete
+	if _, err := h.revokeAllSessions(ctx, userID); err != nil {
+		slog.ErrorContext(ctx, "Error revoking sessions after password reset", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeSessionsNotEnded)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Message: i18n.Text(c.Request.Context(), i18n.MsgPasswordReset),
+	})
+}
+
+// ChangePassword updates the password of the logged-in user after checking
+// the current one. Every session is revoked and a fresh one is returned for
+// the device that made the change.
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Invalid(c, err)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	ctx := c.Request.Context()
+
+	email, err := h.repos.Identity.GetEmail(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading auth user", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	attempt, throttled := h.throttled(c, h.loginGuard, email)
+	if throttled {
+		return
+	}
+	if err := h.checkPassword(ctx, email, req.CurrentPassword); err != nil {
+		if isInvalidCredentials(err) {
+			apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeIncorrectCurrentPassword)
+			return
+		}
+		h.forgive(c, h.loginGuard, attempt)
+		slog.ErrorContext(ctx, "Error checking current password", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	h.forgive(c, h.loginGuard, attempt)
+
+	if err := h.repos.Identity.SetPassword(ctx, userID, req.NewPassword); err != nil {
+		slog.ErrorContext(ctx, "Error updating password", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodePasswordChangeFailed)
+		return
+	}
+
+	cutoff, err := h.revokeAllSessions(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error revoking sessions after password change", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeSessionsNotEnded)
+		return
+	}
+
+	user, err := h.getUserProfile(ctx, userID)
+	if err != nil || user == nil {
+		slog.ErrorContext(ctx, "Error getting user profile", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeProfileFetchFailed)
+		return
+	}
+
+	// O token novo sai depois do corte, mesmo no mesmo milissegundo
+	authResponse, err := h.startSessionAfter(c, user, cutoff)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error creating session", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data:    authResponse,
+		Message: i18n.Text(c.Request.Context(), i18n.MsgPasswordChanged),
+	})
+}
+
+// checkPassword re-authenticates the user, e.g. before a sensitive change.
+func (h *AuthHandler) checkPassword(ctx context.Context, email, password string) error {
+	_, err := h.repos.Identity.SignIn(ctx, email, password)
+	return err
+}
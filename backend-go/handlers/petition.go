@@ -1,67 +1,333 @@
 package handlers
 
 import (
-	"argumentum-backend/models"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
-	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/i18n"
+	"argumentum-backend/internal/metrics"
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
 
 	"github.com/gin-gonic/gin"
-	"github.com/supabase-community/supabase-go"
+	"github.com/go-playground/validator/v10"
 )
 
+// defaultPetitionPageSize is the page size of GET /petitions without limit.
+const defaultPetitionPageSize = 20
+
 type PetitionHandler struct {
-	supabase *supabase.Client
+	repos *store.Repositories
 }
 
-func NewPetitionHandler() *PetitionHandler {
-	supabaseURL := os.Getenv("SUPABASE_URL")
-	supabaseKey := os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
-	
-	if supabaseURL == "" {
-		supabaseURL = "https://mefgswdpeellvaggvttc.supabase.co"
-	}
-	
-	client, err := supabase.NewClient(supabaseURL, supabaseKey, &supabase.ClientOptions{})
-	if err != nil {
-		client = nil
-	}
-
+func NewPetitionHandler(repos *store.Repositories) *PetitionHandler {
 	return &PetitionHandler{
-		supabase: client,
+		repos: repos,
 	}
 }
 
+// GetPetitions lists the petitions the user owns or that belong to one of
+// their teams; administrators see every petition. Team API keys only see
+// the petitions of their team.
 func (h *PetitionHandler) GetPetitions(c *gin.Context) {
-	// For now, return empty array
+	var query models.PetitionListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		var invalid validator.ValidationErrors
+		if errors.As(err, &invalid) {
+			apierror.Invalid(c, err)
+			return
+		}
+		apierror.Respond(c, http.StatusBadRequest, models.ErrCodeInvalidQuery)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultPetitionPageSize
+	}
+
+	filter := store.PetitionFilter{
+		All:       c.GetBool("is_admin"),
+		Status:    query.Status,
+		Ascending: query.Order == "asc",
+		Limit:     query.Limit,
+		Offset:    (query.Page - 1) * query.Limit,
+	}
+	if c.GetString("api_key_team_id") == "" {
+		filter.UserID = c.GetString("user_id")
+	}
+	for teamID := range callerTeams(c) {
+		filter.TeamIDs = append(filter.TeamIDs, teamID)
+	}
+
+	petitions, total, err := h.repos.Petitions.List(c.Request.Context(), filter)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error listing petitions", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodePetitionsFetchFailed)
+		return
+	}
+
 	c.JSON(http.StatusOK, models.ApiResponse{
-		Data: []interface{}{},
+		Data: models.PetitionList{
+			Petitions:  petitions,
+			Total:      total,
+			Page:       query.Page,
+			Limit:      query.Limit,
+			TotalPages: (total + query.Limit - 1) / query.Limit,
+		},
 	})
 }
 
+// CreatePetition creates a petition in one of the user's teams and debits
+// models.PetitionCost tokens from the team owner, who pays for the team.
 func (h *PetitionHandler) CreatePetition(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Endpoint not implemented yet",
+	var req models.CreatePetitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Invalid(c, err)
+		return
+	}
+	if _, isMember := callerTeams(c)[req.TeamID]; !isMember {
+		apierror.Respond(c, http.StatusForbidden, models.ErrCodeTeamAccessDenied)
+		return
+	}
+	ctx := c.Request.Context()
+
+	ownerID, err := h.teamOwnerID(ctx, req.TeamID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading team members", "team_id", req.TeamID, "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeTeamOwnerFetchFailed)
+		return
+	}
+	if ownerID == "" {
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodeTeamOwnerNotFound)
+		return
+	}
+
+	now := time.Now().UTC()
+	teamID := req.TeamID
+	hasProcess := req.HasProcess != nil && *req.HasProcess
+	petition := &models.Petition{
+		ID:           utils.GenerateID(),
+		UserID:       c.GetString("user_id"),
+		TeamID:       &teamID,
+		Title:        strings.TrimSpace(req.Title),
+		Description:  strings.TrimSpace(req.Description),
+		Content:      req.Content,
+		Category:     req.Category,
+		LegalArea:    req.LegalArea,
+		PetitionType: req.PetitionType,
+		Target:       req.Target,
+		HasProcess:   &hasProcess,
+		FormType:     req.FormType,
+		FormAnswers:  req.FormAnswers,
+		Status:       models.PetitionStatusPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	// Número do processo só faz sentido quando há processo
+	if hasProcess {
+		petition.ProcessNumber = req.ProcessNumber
+	}
+	if len(petition.FormAnswers) == 0 || string(petition.FormAnswers) == "null" {
+		petition.FormAnswers = []byte("{}")
+	}
+
+	err = h.repos.Petitions.Create(ctx, petition, ownerID, models.PetitionCost)
+	if errors.Is(err, store.ErrInsufficientTokens) {
+		apierror.Respond(c, http.StatusPaymentRequired, models.ErrCodeInsufficientTokens, models.PetitionCost)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Error creating petition", "team_id", req.TeamID, "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodePetitionCreateFailed)
+		return
+	}
+	metrics.TokenDebit("petition", models.PetitionCost)
+
+	c.JSON(http.StatusCreated, models.ApiResponse{
+		Data: petition,
 	})
 }
 
+// GetPetitionByID returns the petition with its author, attachments,
+// comments and documents. Like the edge function it replaces, a part that
+// fails to load comes back empty instead of failing the request.
 func (h *PetitionHandler) GetPetitionByID(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Endpoint not implemented yet",
+	petition, ok := h.accessiblePetition(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	detail := models.PetitionDetail{
+		Petition:    *petition,
+		Attachments: []models.PetitionAttachment{},
+		Comments:    []models.PetitionComment{},
+		Documents:   []models.PetitionDocument{},
+	}
+
+	profile, err := h.repos.Profiles.Get(ctx, petition.UserID)
+	switch {
+	case err == nil:
+		detail.User = &models.PetitionAuthor{ID: profile.ID, Name: profile.Name, Email: profile.Email}
+	case !errors.Is(err, store.ErrNotFound):
+		slog.ErrorContext(ctx, "Error loading petition author", "petition_id", petition.ID, "error", err)
+	}
+	if attachments, err := h.repos.Documents.ListAttachments(ctx, petition.ID); err != nil {
+		slog.ErrorContext(ctx, "Error loading petition attachments", "petition_id", petition.ID, "error", err)
+	} else if attachments != nil {
+		detail.Attachments = attachments
+	}
+	if comments, err := h.repos.Documents.ListComments(ctx, petition.ID); err != nil {
+		slog.ErrorContext(ctx, "Error loading petition comments", "petition_id", petition.ID, "error", err)
+	} else if comments != nil {
+		detail.Comments = comments
+	}
+	if documents, err := h.repos.Documents.ListDocuments(ctx, petition.ID); err != nil {
+		slog.ErrorContext(ctx, "Error loading petition documents", "petition_id", petition.ID, "error", err)
+	} else if documents != nil {
+		sort.Slice(documents, func(i, j int) bool { return documents[i].CreatedAt.After(documents[j].CreatedAt) })
+		detail.Documents = documents
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: detail,
 	})
 }
 
+// UpdatePetition changes the editable fields of a petition. Whoever can see
+// the petition can edit it.
 func (h *PetitionHandler) UpdatePetition(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Endpoint not implemented yet",
+	var req models.UpdatePetitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Invalid(c, err)
+		return
+	}
+	petition, ok := h.accessiblePetition(c)
+	if !ok {
+		return
+	}
+
+	if req.Title != nil {
+		petition.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		petition.Description = strings.TrimSpace(*req.Description)
+	}
+	setIfPresent(&petition.Content, req.Content)
+	setIfPresent(&petition.Category, req.Category)
+	setIfPresent(&petition.LegalArea, req.LegalArea)
+	setIfPresent(&petition.PetitionType, req.PetitionType)
+	setIfPresent(&petition.Target, req.Target)
+	setIfPresent(&petition.ProcessNumber, req.ProcessNumber)
+	setIfPresent(&petition.FormType, req.FormType)
+	if req.HasProcess != nil {
+		petition.HasProcess = req.HasProcess
+	}
+	if len(req.FormAnswers) > 0 && string(req.FormAnswers) != "null" {
+		petition.FormAnswers = req.FormAnswers
+	}
+	if req.Status != nil {
+		petition.Status = *req.Status
+	}
+	petition.UpdatedAt = time.Now().UTC()
+
+	err := h.repos.Petitions.Update(c.Request.Context(), petition)
+	if errors.Is(err, store.ErrNotFound) {
+		// Removida entre a leitura e a atualização
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodePetitionNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error updating petition", "petition_id", petition.ID, "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodePetitionUpdateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: petition,
 	})
 }
 
+// DeletePetition removes a petition. Team members can see and edit it, but
+// only its author or an administrator can delete it.
 func (h *PetitionHandler) DeletePetition(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Endpoint not implemented yet",
+	petition, ok := h.accessiblePetition(c)
+	if !ok {
+		return
+	}
+	if !c.GetBool("is_admin") && petition.UserID != c.GetString("user_id") {
+		apierror.Respond(c, http.StatusForbidden, models.ErrCodePetitionDeleteForbidden)
+		return
+	}
+
+	if err := h.repos.Petitions.Delete(c.Request.Context(), petition.ID); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error deleting petition", "petition_id", petition.ID, "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodePetitionDeleteFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Message: i18n.Text(c.Request.Context(), i18n.MsgPetitionDeleted),
 	})
 }
 
+// accessiblePetition loads the petition named by the :id parameter,
+// answering 404 when it does not exist and 403 when the caller cannot
+// access it.
+func (h *PetitionHandler) accessiblePetition(c *gin.Context) (*models.Petition, bool) {
+	petition, err := h.repos.Petitions.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodePetitionNotFound)
+		return nil, false
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error loading petition", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return nil, false
+	}
+	if !canAccessPetition(c, petition) {
+		apierror.Respond(c, http.StatusForbidden, models.ErrCodePetitionAccessDenied)
+		return nil, false
+	}
+	return petition, true
+}
+
+// canAccessPetition tells whether the caller is the petition's author, a
+// member of its team or an administrator. Team API keys only reach the
+// petitions of their team, even those their user wrote.
+func canAccessPetition(c *gin.Context, petition *models.Petition) bool {
+	if c.GetBool("is_admin") {
+		return true
+	}
+	if petition.TeamID != nil {
+		if _, isMember := callerTeams(c)[*petition.TeamID]; isMember {
+			return true
+		}
+	}
+	return petition.UserID == c.GetString("user_id") && c.GetString("api_key_team_id") == ""
+}
+
+// callerTeams returns the caller's teams and roles set by AuthMiddleware.
+func callerTeams(c *gin.Context) map[string]string {
+	teams, _ := c.Get("team_roles")
+	roles, _ := teams.(map[string]string)
+	return roles
+}
+
+func setIfPresent(field **string, value *string) {
+	if value != nil {
+		*field = value
+	}
+}
+
 func (h *PetitionHandler) GetTeams(c *gin.Context) {
 	c.JSON(http.StatusOK, models.ApiResponse{
 		Data: []interface{}{},
@@ -69,131 +335,52 @@ func (h *PetitionHandler) GetTeams(c *gin.Context) {
 }
 
 func (h *PetitionHandler) CreateTeam(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Endpoint not implemented yet",
-	})
+	apierror.Respond(c, http.StatusNotImplemented, models.ErrCodeNotImplemented)
 }
 
 func (h *PetitionHandler) GetTeamByID(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Endpoint not implemented yet",
-	})
+	apierror.Respond(c, http.StatusNotImplemented, models.ErrCodeNotImplemented)
 }
 
 func (h *PetitionHandler) UpdateTeam(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Endpoint not implemented yet",
-	})
+	apierror.Respond(c, http.StatusNotImplemented, models.ErrCodeNotImplemented)
 }
 
 func (h *PetitionHandler) DeleteTeam(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Endpoint not implemented yet",
-	})
+	apierror.Respond(c, http.StatusNotImplemented, models.ErrCodeNotImplemented)
 }
 
 func (h *PetitionHandler) GetTeamTokenBalance(c *gin.Context) {
-	if h.supabase == nil {
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Error: "Erro interno do servidor",
-		})
-		return
-	}
+	ctx := c.Request.Context()
 
 	// Obter ID da equipe dos parâmetros
 	teamID := c.Param("id")
 	if teamID == "" {
-		c.JSON(http.StatusBadRequest, models.ApiResponse{
-			Error: "ID da equipe é obrigatório",
-		})
-		return
-	}
-
-	// Obter ID do usuário do contexto (definido pelo middleware de autenticação)
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, models.ApiResponse{
-			Error: "Usuário não autenticado",
-		})
+		apierror.Respond(c, http.StatusBadRequest, models.ErrCodeTeamIDRequired)
 		return
 	}
 
-	userIDStr, ok := userID.(string)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Error: "Erro interno: ID do usuário inválido",
-		})
-		return
-	}
-
-	// Verificar se o usuário é membro da equipe
-	var membershipResult []map[string]interface{}
-	_, err := h.supabase.From("team_members").
-		Select("id").
-		Eq("team_id", teamID).
-		Eq("user_id", userIDStr).
-		ExecuteTo(&membershipResult)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Error: "Erro ao verificar associação à equipe",
-		})
-		return
-	}
-
-	if len(membershipResult) == 0 {
-		c.JSON(http.StatusForbidden, models.ApiResponse{
-			Error: "Sem permissão para acessar esta equipe",
-		})
-		return
-	}
+	// Associação à equipe já verificada por middleware.RequireTeamRole
 
 	// Buscar o proprietário da equipe
-	var ownerResult []map[string]interface{}
-	_, err = h.supabase.From("team_members").
-		Select("user_id").
-		Eq("team_id", teamID).
-		Eq("role", "owner").
-		ExecuteTo(&ownerResult)
-
+	ownerID, err := h.teamOwnerID(ctx, teamID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Error: "Erro ao buscar proprietário da equipe",
-		})
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeTeamOwnerFetchFailed)
 		return
 	}
-
-	if len(ownerResult) == 0 {
-		c.JSON(http.StatusNotFound, models.ApiResponse{
-			Error: "Proprietário da equipe não encontrado",
-		})
+	if ownerID == "" {
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodeTeamOwnerNotFound)
 		return
 	}
 
-	ownerID := ownerResult[0]["user_id"].(string)
-
-	// Buscar saldo de tokens do proprietário
-	var tokenResult []map[string]interface{}
-	_, err = h.supabase.From("user_tokens").
-		Select("tokens").
-		Eq("user_id", ownerID).
-		ExecuteTo(&tokenResult)
+	// Buscar saldo de tokens do proprietário (0 quando não há registro)
+	tokens, err := h.repos.Tokens.Balance(ctx, ownerID)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Error: "Erro ao buscar saldo de tokens",
-		})
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeTokenBalanceFetchFailed)
 		return
 	}
 
-	// Se não há registro de tokens, retornar 0
-	tokens := 0
-	if len(tokenResult) > 0 {
-		if tokenValue, ok := tokenResult[0]["tokens"].(float64); ok {
-			tokens = int(tokenValue)
-		}
-	}
-
 	c.JSON(http.StatusOK, models.ApiResponse{
 		Data: map[string]interface{}{
 			"tokens": tokens,
@@ -201,6 +388,21 @@ func (h *PetitionHandler) GetTeamTokenBalance(c *gin.Context) {
 	})
 }
 
+// teamOwnerID returns the owner of the team, who pays for its petitions, or
+// "" when the team has none.
+func (h *PetitionHandler) teamOwnerID(ctx context.Context, teamID string) (string, error) {
+	members, err := h.repos.TeamMembers.ListByTeams(ctx, teamID)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range members {
+		if m.Role == models.TeamRoleOwner {
+			return m.UserID, nil
+		}
+	}
+	return "", nil
+}
+
 func (h *PetitionHandler) GetPetitionSettings(c *gin.Context) {
 	c.JSON(http.StatusOK, models.ApiResponse{
 		Data: map[string]interface{}{},
@@ -208,7 +410,5 @@ func (h *PetitionHandler) GetPetitionSettings(c *gin.Context) {
 }
 
 func (h *PetitionHandler) UpdatePetitionSettings(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Endpoint not implemented yet",
-	})
+	apierror.Respond(c, http.StatusNotImplemented, models.ErrCodeNotImplemented)
 }
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// caller is who a test request authenticates as, in the context keys
+// AuthMiddleware sets.
+type caller struct {
+	userID    string
+	admin     bool
+	teams     map[string]string
+	keyTeamID string // set for team API keys
+}
+
+func petitionRouter(t *testing.T, as caller) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	teamID, otherTeamID := "team-1", "team-2"
+	old := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	repos, err := store.NewMemoryRepositories(&store.Seed{
+		Users: []store.SeedUser{
+			{Profile: models.Profile{ID: "author", Email: "author@x.test", Name: "Autora"}, Password: "x"},
+		},
+		Petitions: []models.Petition{
+			{ID: "team-petition", UserID: "author", TeamID: &teamID, Title: "Equipe"},
+			{ID: "solo-petition", UserID: "author", Title: "Sem equipe"},
+			{ID: "other-petition", UserID: "outsider", TeamID: &otherTeamID, Title: "Outra equipe"},
+		},
+		Documents: []models.PetitionDocument{
+			{ID: "doc-old", PetitionID: "team-petition", FileName: "a.pdf", CreatedAt: old},
+			{ID: "doc-new", PetitionID: "team-petition", FileName: "b.pdf", CreatedAt: old.Add(time.Hour)},
+		},
+		Comments: []models.PetitionComment{
+			{ID: "comment-1", PetitionID: "team-petition", AuthorID: "author", Content: "Revisar"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewPetitionHandler(repos)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", as.userID)
+		c.Set("is_admin", as.admin)
+		c.Set("team_roles", as.teams)
+		c.Set("api_key_team_id", as.keyTeamID)
+	})
+	r.GET("/petitions", h.GetPetitions)
+	r.GET("/petitions/:id", h.GetPetitionByID)
+	r.DELETE("/petitions/:id", h.DeletePetition)
+	return r
+}
+
+func TestPetitionAccessRules(t *testing.T) {
+	author := caller{userID: "author", teams: map[string]string{"team-1": models.TeamRoleOwner}}
+	teammate := caller{userID: "teammate", teams: map[string]string{"team-1": "operador"}}
+	admin := caller{userID: "admin", admin: true}
+	authorTeamKey := caller{userID: "author", teams: map[string]string{"team-1": models.TeamRoleOwner}, keyTeamID: "team-1"}
+
+	cases := []struct {
+		name   string
+		as     caller
+		method string
+		id     string
+		want   int
+	}{
+		{"author reads team petition", author, http.MethodGet, "team-petition", http.StatusOK},
+		{"author reads own petition without team", author, http.MethodGet, "solo-petition", http.StatusOK},
+		{"author reads other team's petition", author, http.MethodGet, "other-petition", http.StatusForbidden},
+		{"unknown petition", author, http.MethodGet, "missing", http.StatusNotFound},
+		{"teammate reads team petition", teammate, http.MethodGet, "team-petition", http.StatusOK},
+		{"teammate reads author's petition without team", teammate, http.MethodGet, "solo-petition", http.StatusForbidden},
+		{"teammate deletes author's petition", teammate, http.MethodDelete, "team-petition", http.StatusForbidden},
+		{"admin reads any petition", admin, http.MethodGet, "other-petition", http.StatusOK},
+		{"team key reads team petition", authorTeamKey, http.MethodGet, "team-petition", http.StatusOK},
+		{"team key reads its user's petition outside the team", authorTeamKey, http.MethodGet, "solo-petition", http.StatusForbidden},
+		{"author deletes own petition", author, http.MethodDelete, "solo-petition", http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			petitionRouter(t, tc.as).ServeHTTP(w, httptest.NewRequest(tc.method, "/petitions/"+tc.id, nil))
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestPetitionListScope(t *testing.T) {
+	cases := []struct {
+		name string
+		as   caller
+		want int
+	}{
+		{"session sees own and team petitions", caller{userID: "author", teams: map[string]string{"team-1": models.TeamRoleOwner}}, 2},
+		{"team key sees only the team", caller{userID: "author", teams: map[string]string{"team-1": models.TeamRoleOwner}, keyTeamID: "team-1"}, 1},
+		{"admin sees everything", caller{userID: "admin", admin: true}, 3},
+		{"no teams, no petitions", caller{userID: "nobody"}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			petitionRouter(t, tc.as).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/petitions", nil))
+			var body struct {
+				Data models.PetitionList `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || w.Code != http.StatusOK {
+				t.Fatalf("status %d, body %s", w.Code, w.Body)
+			}
+			if body.Data.Total != tc.want || len(body.Data.Petitions) != tc.want {
+				t.Fatalf("total = %d with %d petitions, want %d", body.Data.Total, len(body.Data.Petitions), tc.want)
+			}
+		})
+	}
+}
+
+func TestGetPetitionByIDDetail(t *testing.T) {
+	w := httptest.NewRecorder()
+	as := caller{userID: "author", teams: map[string]string{"team-1": models.TeamRoleOwner}}
+	petitionRouter(t, as).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/petitions/team-petition", nil))
+	var body struct {
+		Data models.PetitionDetail `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("status %d, body %s", w.Code, w.Body)
+	}
+	detail := body.Data
+	if detail.ID != "team-petition" || detail.User == nil || detail.User.Name != "Autora" {
+		t.Fatalf("petition %q by %+v, want team-petition by Autora", detail.ID, detail.User)
+	}
+	if len(detail.Comments) != 1 || detail.Attachments == nil {
+		t.Fatalf("comments = %+v, attachments = %v", detail.Comments, detail.Attachments)
+	}
+	if len(detail.Documents) != 2 || detail.Documents[0].ID != "doc-new" {
+		t.Fatalf("documents = %+v, want newest first", detail.Documents)
+	}
+}
@@ -1,146 +1,68 @@
 package handlers
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
+	"argumentum-backend/internal/apierror"
 	"argumentum-backend/models"
+	"argumentum-backend/r2"
+	"argumentum-backend/store"
 	"context"
+	"errors"
+	"log/slog"
 	"net/http"
-	"os"
+	"sync"
 
 	"github.com/gin-gonic/gin"
-	"github.com/supabase-community/supabase-go"
 )
 
 type ProfileHandler struct {
-	supabase *supabase.Client
+	repos   *store.Repositories
+	exports store.DataExportStore
+	// r2 reads original files for data exports and stores the finished ZIPs;
+	// nil when not configured.
+	r2          *r2.Client
+	exportSlots chan struct{}
+	// builds tracks the exports in progress; cancelBuilds aborts them when
+	// shutdown cannot wait any longer.
+	builds       sync.WaitGroup
+	buildCtx     context.Context
+	cancelBuilds context.CancelFunc
 }
 
-func NewProfileHandler() *ProfileHandler {
-	supabaseURL := os.Getenv("SUPABASE_URL")
-	supabaseKey := os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
-	
-	if supabaseURL == "" {
-		supabaseURL = "https://mefgswdpeellvaggvttc.supabase.co"
-	}
-	
-	client, err := supabase.NewClient(supabaseURL, supabaseKey, &supabase.ClientOptions{})
-	if err != nil {
-		client = nil
-	}
-
+func NewProfileHandler(repos *store.Repositories, exports store.DataExportStore, storage *r2.Client) *ProfileHandler {
+	buildCtx, cancelBuilds := context.WithCancel(context.Background())
 	return &ProfileHandler{
-		supabase: client,
-	}
-}
-
-// --- Aux Function: doSupabaseREST ---
-func (h *ProfileHandler) doSupabaseREST(ctx context.Context, method, path string, payload interface{}, result interface{}) error {
-	supabaseURL := os.Getenv("SUPABASE_URL")
-	supabaseKey := os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
-	if supabaseURL == "" {
-		supabaseURL = "https://mefgswdpeellvaggvttc.supabase.co"
-	}
-	url := supabaseURL + path
-
-	var body io.Reader
-	if payload != nil {
-		buf, err := json.Marshal(payload)
-		if err != nil {
-			return err
-		}
-		body = bytes.NewBuffer(buf)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("apikey", supabaseKey)
-	req.Header.Set("Authorization", "Bearer "+supabaseKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+		repos:        repos,
+		exports:      exports,
+		r2:           storage,
+		exportSlots:  make(chan struct{}, maxConcurrentExports),
+		buildCtx:     buildCtx,
+		cancelBuilds: cancelBuilds,
 	}
-	defer resp.Body.Close()
-
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return &models.ApiError{
-			Status:  resp.StatusCode,
-			Message: string(respBytes),
-		}
-	}
-
-	if result != nil {
-		return json.Unmarshal(respBytes, result)
-	}
-	return nil
 }
 
 func (h *ProfileHandler) GetProfile(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
-		c.JSON(http.StatusUnauthorized, models.ApiResponse{
-			Error: "Usuário não autenticado",
-		})
+		apierror.Respond(c, http.StatusUnauthorized, models.ErrCodeUnauthenticated)
 		return
 	}
 
-	var profiles []struct {
-		ID      string `json:"id"`
-		Email   string `json:"email"`
-		Name    string `json:"name"`
-		IsAdmin bool   `json:"is_admin"`
-	}
-
-	ctx := context.Background()
-	query := map[string]string{"id": "eq." + userID}
-	queryString := "?"
-	for k, v := range query {
-		queryString += k + "=" + v + "&"
-	}
-	url := "/rest/v1/profiles" + queryString[:len(queryString)-1]
-	if h.supabase != nil {
-		err := h.doSupabaseREST(ctx, "GET", url, nil, &profiles)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ApiResponse{
-				Error: "Erro ao buscar perfil",
-			})
-			return
-		}
-	}
-
-	if len(profiles) == 0 {
-		c.JSON(http.StatusNotFound, models.ApiResponse{
-			Error: "Perfil não encontrado",
-		})
+	profile, err := h.repos.Profiles.Get(c.Request.Context(), userID)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodeProfileNotFound)
 		return
 	}
-
-	profile := profiles[0]
-	user := models.User{
-		ID:      profile.ID,
-		Email:   profile.Email,
-		Name:    profile.Name,
-		IsAdmin: profile.IsAdmin,
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error loading profile", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeProfileFetchFailed)
+		return
 	}
 
 	c.JSON(http.StatusOK, models.ApiResponse{
-		Data: user,
+		Data: profile.User(),
 	})
 }
 
 func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Endpoint not implemented yet",
-	})
+	apierror.Respond(c, http.StatusNotImplemented, models.ErrCodeNotImplemented)
 }
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// refreshFixture is a handler on the memory stores with one logged-in
+// session of user u1.
+type refreshFixture struct {
+	h       *AuthHandler
+	tokens  *store.MemoryRefreshTokenStore
+	revoked store.RevocationList
+	session *models.Session
+	router  *gin.Engine
+}
+
+func newRefreshFixture(t *testing.T, sessionExpiresIn time.Duration) *refreshFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	repos, err := store.NewMemoryRepositories(&store.Seed{
+		Users: []store.SeedUser{{Profile: models.Profile{ID: "u1", Email: "u1@x.test", Name: "U1"}, Password: "x"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &refreshFixture{
+		tokens:  store.NewMemoryRefreshTokenStore(),
+		revoked: store.NewMemoryRevocationList(),
+	}
+	f.h = NewAuthHandler(AuthDeps{Repositories: repos, RefreshTokens: f.tokens, Revocations: f.revoked})
+
+	now := time.Now().UTC()
+	f.session = &models.Session{ID: "s1", UserID: "u1", CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(sessionExpiresIn)}
+	if err := f.tokens.CreateSession(context.Background(), f.session); err != nil {
+		t.Fatal(err)
+	}
+	f.router = gin.New()
+	f.router.POST("/auth/refresh", f.h.RefreshToken)
+	return f
+}
+
+func (f *refreshFixture) issue(t *testing.T) string {
+	t.Helper()
+	raw, err := f.h.issueRefreshToken(context.Background(), f.session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+// refresh posts the token and returns the status, error code and the new
+// refresh token.
+func (f *refreshFixture) refresh(t *testing.T, raw string) (int, string, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	body := `{"refreshToken":"` + raw + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	f.router.ServeHTTP(w, req)
+
+	var resp struct {
+		Code string `json:"code"`
+		Data struct {
+			RefreshToken string `json:"refreshToken"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid body %s: %v", w.Body, err)
+	}
+	return w.Code, resp.Code, resp.Data.RefreshToken
+}
+
+func TestRefreshRotation(t *testing.T) {
+	f := newRefreshFixture(t, time.Hour)
+	first := f.issue(t)
+
+	status, _, second := f.refresh(t, first)
+	if status != http.StatusOK || second == "" || second == first {
+		t.Fatalf("first refresh = %d with token %q, want 200 and a new token", status, second)
+	}
+	status, _, third := f.refresh(t, second)
+	if status != http.StatusOK || third == "" || third == second {
+		t.Fatalf("second refresh = %d with token %q, want 200 and a new token", status, third)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	f := newRefreshFixture(t, time.Hour)
+	stolen := f.issue(t)
+	_, _, current := f.refresh(t, stolen)
+	issuedAt := time.Now().Add(-time.Minute)
+
+	if status, code, _ := f.refresh(t, stolen); status != http.StatusUnauthorized || code != models.ErrCodeSessionRevoked {
+		t.Fatalf("replayed token = %d %q, want 401 %s", status, code, models.ErrCodeSessionRevoked)
+	}
+	// The legitimate holder is logged out as well
+	if status, code, _ := f.refresh(t, current); status != http.StatusUnauthorized || code != models.ErrCodeSessionExpired {
+		t.Fatalf("latest token after reuse = %d %q, want 401 %s", status, code, models.ErrCodeSessionExpired)
+	}
+	session, err := f.tokens.GetSession(context.Background(), f.session.ID)
+	if err != nil || session.RevokedAt == nil {
+		t.Fatalf("session = %+v, %v; want revoked", session, err)
+	}
+	revoked, err := f.revoked.IsRevoked(context.Background(), "jti", f.session.ID, "u1", issuedAt)
+	if err != nil || !revoked {
+		t.Fatalf("access tokens of the session still valid (revoked=%v, err=%v)", revoked, err)
+	}
+}
+
+func TestRefreshRejects(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(t *testing.T, f *refreshFixture) string
+		code  string
+	}{
+		{"unknown token", func(t *testing.T, f *refreshFixture) string { return "not-a-token" }, models.ErrCodeInvalidRefreshToken},
+		{"expired token", func(t *testing.T, f *refreshFixture) string {
+			raw := f.issue(t)
+			expireTokens(t, f, raw)
+			return raw
+		}, models.ErrCodeSessionExpired},
+		{"expired session", func(t *testing.T, f *refreshFixture) string {
+			f.session.ExpiresAt = time.Now().Add(-time.Second)
+			if err := f.tokens.CreateSession(context.Background(), f.session); err != nil {
+				t.Fatal(err)
+			}
+			return f.issue(t)
+		}, models.ErrCodeSessionExpired},
+		{"missing session", func(t *testing.T, f *refreshFixture) string {
+			f.session = &models.Session{ID: "gone", UserID: "u1", ExpiresAt: time.Now().Add(time.Hour)}
+			return f.issue(t)
+		}, models.ErrCodeInvalidRefreshToken},
+		{"revoked session", func(t *testing.T, f *refreshFixture) string {
+			raw := f.issue(t)
+			if err := f.tokens.RevokeSession(context.Background(), f.session.ID, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			return raw
+		}, models.ErrCodeSessionExpired},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newRefreshFixture(t, time.Hour)
+			raw := tc.setup(t, f)
+			if status, code, _ := f.refresh(t, raw); status != http.StatusUnauthorized || code != tc.code {
+				t.Fatalf("refresh = %d %q, want 401 %s", status, code, tc.code)
+			}
+		})
+	}
+}
+
+// brokenSessions fails every session lookup, as when the database is down.
+type brokenSessions struct {
+	store.RefreshTokenStore
+}
+
+func (brokenSessions) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	return nil, errors.New("connection refused")
+}
+
+// A failing session lookup is a server error, not a reason to log the user
+// out.
+func TestRefreshSessionLookupError(t *testing.T) {
+	f := newRefreshFixture(t, time.Hour)
+	raw := f.issue(t)
+	f.h.refreshTokens = brokenSessions{f.tokens}
+
+	if status, code, _ := f.refresh(t, raw); status != http.StatusInternalServerError || code != models.ErrCodeInternal {
+		t.Fatalf("refresh = %d %q, want 500 %s", status, code, models.ErrCodeInternal)
+	}
+}
+
+// expireTokens re-saves the token raw with an expiry in the past.
+func expireTokens(t *testing.T, f *refreshFixture, raw string) {
+	t.Helper()
+	ctx := context.Background()
+	token, err := f.tokens.GetTokenByHash(ctx, utils.HashToken(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token.ExpiresAt = time.Now().Add(-time.Second)
+	if err := f.tokens.CreateToken(ctx, token); err != nil {
+		t.Fatal(err)
+	}
+}
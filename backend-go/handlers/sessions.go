@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/i18n"
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSessions returns the devices where the user is logged in. The session
+// making the request is flagged as current.
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	sessions, err := h.refreshTokens.ListUserSessions(c.Request.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error listing sessions", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeSessionsFetchFailed)
+		return
+	}
+
+	currentID := ""
+	if value, ok := c.Get("claims"); ok {
+		if claims, ok := value.(*utils.Claims); ok {
+			currentID = claims.SessionID
+		}
+	}
+
+	infos := make([]models.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		browser, system := parseUserAgent(session.UserAgent)
+		infos = append(infos, models.SessionInfo{
+			ID:         session.ID,
+			Device:     describeDevice(c.Request.Context(), browser, system, session.UserAgent),
+			Browser:    browser,
+			OS:         system,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentID,
+		})
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Data: infos,
+	})
+}
+
+// DeleteSession ends one of the user's sessions, e.g. on a lost device. Its
+// refresh token stops working and its access tokens are revoked right away.
+func (h *AuthHandler) DeleteSession(c *gin.Context) {
+	userID := c.GetString("user_id")
+	ctx := c.Request.Context()
+
+	session, err := h.refreshTokens.GetSession(ctx, c.Param("id"))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		slog.ErrorContext(ctx, "Error loading session", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	if session == nil || session.UserID != userID || session.RevokedAt != nil {
+		apierror.Respond(c, http.StatusNotFound, models.ErrCodeSessionNotFound)
+		return
+	}
+
+	if err := h.revokeSession(ctx, session.ID); err != nil {
+		slog.ErrorContext(ctx, "Error revoking session", "session_id", session.ID, "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, models.ErrCodeSessionEndFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Message: i18n.Text(c.Request.Context(), i18n.MsgSessionEnded),
+	})
+}
+
+// revokeSession ends the token family and blocks the access tokens already
+// issued for it until they would have expired anyway.
+func (h *AuthHandler) revokeSession(ctx context.Context, sessionID string) error {
+	now := time.Now().UTC()
+	if err := h.refreshTokens.RevokeSession(ctx, sessionID, now); err != nil {
+		return err
+	}
+	return h.revocations.RevokeSession(ctx, sessionID, now.Add(utils.AccessTokenTTL))
+}
+
+// describeDevice builds a short label such as "Chrome em Windows" in the
+// language of ctx. Unknown agents are reported as is.
+func describeDevice(ctx context.Context, browser, system, userAgent string) string {
+	switch {
+	case browser != "" && system != "":
+		return i18n.Text(ctx, i18n.DeviceBrowserOnOS, browser, system)
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	case userAgent != "":
+		return userAgent
+	}
+	return i18n.Text(ctx, i18n.DeviceUnknown)
+}
+
+// parseUserAgent recognizes the browser and operating system of a
+// User-Agent. Either is empty when not recognized.
+func parseUserAgent(userAgent string) (browser, system string) {
+	// Order matters: Edge and Opera also announce Chrome, Chrome announces Safari.
+	browsers := []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	}
+	systems := []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+
+	for _, b := range browsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, s := range systems {
+		if strings.Contains(userAgent, s.token) {
+			system = s.name
+			break
+		}
+	}
+	return browser, system
+}
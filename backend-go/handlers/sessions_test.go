@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"argumentum-backend/internal/i18n"
+)
+
+func TestDescribeDevice(t *testing.T) {
+	const chromeWindows = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"
+	const edgeMac = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36 Edg/120.0"
+
+	cases := []struct {
+		name, userAgent, lang   string
+		browser, system, device string
+	}{
+		{"pt-BR label", chromeWindows, i18n.PtBR, "Chrome", "Windows", "Chrome em Windows"},
+		{"en label", chromeWindows, i18n.En, "Chrome", "Windows", "Chrome on Windows"},
+		{"edge before chrome", edgeMac, i18n.En, "Edge", "macOS", "Edge on macOS"},
+		{"browser only", "curl/8.4.0", i18n.En, "curl", "", "curl"},
+		{"unrecognized", "custom-client/1.0", i18n.En, "", "", "custom-client/1.0"},
+		{"empty in pt-BR", "", i18n.PtBR, "", "", "Dispositivo desconhecido"},
+		{"empty in en", "", i18n.En, "", "", "Unknown device"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			browser, system := parseUserAgent(tc.userAgent)
+			if browser != tc.browser || system != tc.system {
+				t.Fatalf("parseUserAgent = %q, %q; want %q, %q", browser, system, tc.browser, tc.system)
+			}
+			ctx := i18n.WithLanguage(context.Background(), tc.lang)
+			if got := describeDevice(ctx, browser, system, tc.userAgent); got != tc.device {
+				t.Fatalf("describeDevice = %q, want %q", got, tc.device)
+			}
+		})
+	}
+}
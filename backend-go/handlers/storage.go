@@ -2,33 +2,21 @@
 package handlers
 
 import (
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/gateway"
 	"argumentum-backend/models"
 	"net/http"
-	"os"
 
 	"github.com/gin-gonic/gin"
-	"github.com/supabase-community/supabase-go"
 )
 
 type StorageHandler struct {
-	supabase *supabase.Client
+	db *gateway.Client
 }
 
-func NewStorageHandler() *StorageHandler {
-	supabaseURL := os.Getenv("SUPABASE_URL")
-	supabaseKey := os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
-	
-	if supabaseURL == "" {
-		supabaseURL = "https://mefgswdpeellvaggvttc.supabase.co"
-	}
-	
-	client, err := supabase.NewClient(supabaseURL, supabaseKey, &supabase.ClientOptions{})
-	if err != nil {
-		client = nil
-	}
-
+func NewStorageHandler(db *gateway.Client) *StorageHandler {
 	return &StorageHandler{
-		supabase: client,
+		db: db,
 	}
 }
 
@@ -39,25 +27,17 @@ func (h *StorageHandler) GetDocuments(c *gin.Context) {
 }
 
 func (h *StorageHandler) UploadDocument(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Upload endpoint not implemented yet",
-	})
+	apierror.Respond(c, http.StatusNotImplemented, models.ErrCodeNotImplemented)
 }
 
 func (h *StorageHandler) DeleteDocument(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Delete endpoint not implemented yet",
-	})
+	apierror.Respond(c, http.StatusNotImplemented, models.ErrCodeNotImplemented)
 }
 
 func (h *StorageHandler) GetSignedURL(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Signed URL endpoint not implemented yet",
-	})
+	apierror.Respond(c, http.StatusNotImplemented, models.ErrCodeNotImplemented)
 }
 
 func (h *StorageHandler) DeleteFile(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, models.ApiResponse{
-		Error: "Delete file endpoint not implemented yet",
-	})
+	apierror.Respond(c, http.StatusNotImplemented, models.ErrCodeNotImplemented)
 }
@@ -0,0 +1,142 @@
+// Package apierror writes error responses: a stable code from models, its
+// message in the request's language and, for invalid bodies, one entry per
+// offending field.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"argumentum-backend/internal/i18n"
+	"argumentum-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report fields by their JSON name, as the client sent them.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonName)
+	}
+}
+
+// New builds the response for code, formatting args into its message.
+func New(c *gin.Context, code string, args ...interface{}) models.ApiResponse {
+	return models.ApiResponse{
+		Code:  code,
+		Error: i18n.Text(c.Request.Context(), code, args...),
+	}
+}
+
+// Respond answers with status and code.
+func Respond(c *gin.Context, status int, code string, args ...interface{}) {
+	c.JSON(status, New(c, code, args...))
+}
+
+// Abort answers with status and code and stops the handler chain.
+func Abort(c *gin.Context, status int, code string, args ...interface{}) {
+	c.AbortWithStatusJSON(status, New(c, code, args...))
+}
+
+// Invalid answers 400 for a body that ShouldBindJSON rejected: malformed
+// JSON gets invalid_json, anything else validation_failed with the fields.
+func Invalid(c *gin.Context, err error) {
+	fields, ok := fieldErrors(c, err)
+	if !ok {
+		Respond(c, http.StatusBadRequest, models.ErrCodeInvalidJSON)
+		return
+	}
+	resp := New(c, models.ErrCodeValidationFailed)
+	resp.Fields = fields
+	c.JSON(http.StatusBadRequest, resp)
+}
+
+func fieldErrors(c *gin.Context, err error) ([]models.FieldError, bool) {
+	lang := i18n.Language(c.Request.Context())
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []models.FieldError{{
+			Field:   typeErr.Field,
+			Code:    models.FieldCodeType,
+			Message: i18n.T(lang, i18n.FieldType),
+		}}, true
+	}
+
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		// Syntax errors, io.EOF for an empty body, and so on
+		return nil, false
+	}
+	fields := make([]models.FieldError, 0, len(invalid))
+	for _, fe := range invalid {
+		fields = append(fields, fieldError(lang, fe))
+	}
+	return fields, true
+}
+
+func fieldError(lang string, fe validator.FieldError) models.FieldError {
+	field := models.FieldError{Field: fe.Field()}
+	switch fe.Tag() {
+	case "required":
+		field.Code = models.FieldCodeRequired
+		field.Message = i18n.T(lang, i18n.FieldRequired)
+	case "email":
+		field.Code = models.FieldCodeEmail
+		field.Message = i18n.T(lang, i18n.FieldEmail)
+	case "min", "max":
+		field.Code = fe.Tag()
+		field.Param = fe.Param()
+		field.Message = i18n.T(lang, limitKey(fe.Tag(), fe.Kind()), fe.Param())
+	default:
+		field.Code = models.FieldCodeInvalid
+		field.Message = i18n.T(lang, i18n.FieldInvalid)
+	}
+	return field
+}
+
+// limitKey picks the min/max message for what the rule measures.
+func limitKey(tag string, kind reflect.Kind) string {
+	min := tag == "min"
+	switch kind {
+	case reflect.String:
+		if min {
+			return i18n.FieldMinString
+		}
+		return i18n.FieldMaxString
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if min {
+			return i18n.FieldMinItems
+		}
+		return i18n.FieldMaxItems
+	}
+	if min {
+		return i18n.FieldMinNumber
+	}
+	return i18n.FieldMaxNumber
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// Required answers 400 validation_failed for a field the binding rules
+// cannot express, e.g. one of two fields being mandatory.
+func Required(c *gin.Context, field string) {
+	resp := New(c, models.ErrCodeValidationFailed)
+	resp.Fields = []models.FieldError{{
+		Field:   field,
+		Code:    models.FieldCodeRequired,
+		Message: i18n.Text(c.Request.Context(), i18n.FieldRequired),
+	}}
+	c.JSON(http.StatusBadRequest, resp)
+}
@@ -0,0 +1,555 @@
+// Package config loads the server settings once at startup: an optional JSON
+// file (CONFIG_FILE) overridden by environment variables, validated before
+// anything else is built so a misconfigured deployment refuses to boot
+// instead of failing on the first request.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"argumentum-backend/internal/logging"
+	"argumentum-backend/internal/tracing"
+)
+
+// Environments accepted in APP_ENV.
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Backends accepted in DATA_STORE and AUTH_STORE.
+const (
+	StoreSupabase = "supabase"
+	StoreMemory   = "memory"
+)
+
+// defaultJWTSecret is the secret older builds fell back to; a deployment still
+// carrying it is treated as unconfigured.
+const defaultJWTSecret = "argumentum-default-jwt-secret-change-in-production"
+
+type Config struct {
+	Env  string    `json:"env"`
+	Port string    `json:"port"`
+	Log  LogConfig `json:"log"`
+
+	Server   ServerConfig   `json:"server"`
+	CORS     CORSConfig     `json:"cors"`
+	Security SecurityConfig `json:"security"`
+	Health   HealthConfig   `json:"health"`
+	Metrics  MetricsConfig  `json:"metrics"`
+	Tracing  TracingConfig  `json:"tracing"`
+	Supabase SupabaseConfig `json:"supabase"`
+	Store    StoreConfig    `json:"store"`
+	JWT      JWTConfig      `json:"jwt"`
+	MFA      MFAConfig      `json:"mfa"`
+	R2       R2Config       `json:"r2"`
+
+	AccountDeletion AccountDeletionConfig `json:"accountDeletion"`
+}
+
+type LogConfig struct {
+	// Level is debug, info, warn or error; Format is json or text.
+	Level  string `json:"level"`
+	Format string `json:"format"`
+}
+
+type ServerConfig struct {
+	ReadHeaderTimeout Duration `json:"readHeaderTimeout"`
+	ReadTimeout       Duration `json:"readTimeout"`
+	WriteTimeout      Duration `json:"writeTimeout"`
+	IdleTimeout       Duration `json:"idleTimeout"`
+	// LongRequestTimeout replaces ReadTimeout and WriteTimeout on the routes
+	// that move whole files through the server: document upload.
+	LongRequestTimeout Duration `json:"longRequestTimeout"`
+	// PreStopDelay is how long the server keeps serving after SIGTERM while
+	// /health/ready answers 503, so load balancers stop sending traffic
+	// before it stops accepting connections.
+	PreStopDelay Duration `json:"preStopDelay"`
+	// ShutdownTimeout bounds the drain of in-flight requests and background
+	// jobs after SIGTERM.
+	ShutdownTimeout Duration `json:"shutdownTimeout"`
+	// TrustedProxies are the IPs or CIDRs of the reverse proxies whose
+	// X-Forwarded-For is believed. Empty trusts none: the client IP used by
+	// the brute-force limits and session metadata is the peer address.
+	TrustedProxies []string `json:"trustedProxies"`
+}
+
+type CORSConfig struct {
+	// AllowedOrigins are exact origins (https://app.example.com) or patterns
+	// with a wildcard subdomain (https://*.vercel.app) for preview
+	// deployments. Outside production it defaults to the local frontends.
+	AllowedOrigins []string `json:"allowedOrigins"`
+	// MaxAge is how long browsers may cache a preflight response.
+	MaxAge Duration `json:"maxAge"`
+}
+
+type SecurityConfig struct {
+	// HSTSMaxAge is sent in Strict-Transport-Security in production; zero
+	// leaves the header out.
+	HSTSMaxAge Duration `json:"hstsMaxAge"`
+}
+
+type HealthConfig struct {
+	// CacheTTL is how long a readiness check result is reused; CheckTimeout
+	// bounds each check.
+	CacheTTL     Duration `json:"cacheTtl"`
+	CheckTimeout Duration `json:"checkTimeout"`
+}
+
+type MetricsConfig struct {
+	// Token is the Bearer token /metrics requires; empty leaves it open.
+	Token string `json:"token"`
+}
+
+type TracingConfig struct {
+	// Exporter is "none" (spans are not recorded) or "otlp".
+	Exporter    string  `json:"exporter"`
+	Endpoint    string  `json:"endpoint"`
+	ServiceName string  `json:"serviceName"`
+	SampleRatio float64 `json:"sampleRatio"`
+}
+
+type SupabaseConfig struct {
+	URL            string `json:"url"`
+	ServiceRoleKey string `json:"serviceRoleKey"`
+	// Timeout bounds each attempt of a call; MaxRetries how many times a
+	// transient failure is retried.
+	Timeout    Duration `json:"timeout"`
+	MaxRetries int      `json:"maxRetries"`
+}
+
+type StoreConfig struct {
+	// Data is where the repositories live; empty picks memory without a
+	// service role key. Auth covers sessions, revocations and API keys and
+	// follows Data when empty.
+	Data     string `json:"data"`
+	Auth     string `json:"auth"`
+	SeedFile string `json:"seedFile"`
+}
+
+type JWTConfig struct {
+	SigningAlg string `json:"signingAlg"`
+	// KeysDir holds the PEM signing keys; empty means an ephemeral key.
+	KeysDir          string   `json:"keysDir"`
+	RotationInterval Duration `json:"rotationInterval"`
+	// LegacySecret is JWT_SECRET, no longer used for signing. It is only
+	// checked so the old default is not mistaken for a real setup.
+	LegacySecret string `json:"-"`
+}
+
+type MFAConfig struct {
+	EncryptionKey string `json:"encryptionKey"`
+}
+
+type R2Config struct {
+	AccountID       string `json:"accountId"`
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	Bucket          string `json:"bucket"`
+}
+
+type AccountDeletionConfig struct {
+	GraceDays int `json:"graceDays"`
+}
+
+// Duration reads Go duration strings ("720h") from the config file.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Defaults returns the settings used when neither the file nor the
+// environment sets a value.
+func Defaults() *Config {
+	return &Config{
+		Env:  EnvDevelopment,
+		Port: "8080",
+		Log: LogConfig{
+			Level:  "info",
+			Format: logging.FormatJSON,
+		},
+		Server: ServerConfig{
+			ReadHeaderTimeout:  Duration(5 * time.Second),
+			ReadTimeout:        Duration(30 * time.Second),
+			WriteTimeout:       Duration(60 * time.Second),
+			IdleTimeout:        Duration(120 * time.Second),
+			LongRequestTimeout: Duration(10 * time.Minute),
+			PreStopDelay:       Duration(5 * time.Second),
+			ShutdownTimeout:    Duration(30 * time.Second),
+		},
+		CORS: CORSConfig{
+			MaxAge: Duration(12 * time.Hour),
+		},
+		Security: SecurityConfig{
+			HSTSMaxAge: Duration(365 * 24 * time.Hour),
+		},
+		Health: HealthConfig{
+			CacheTTL:     Duration(10 * time.Second),
+			CheckTimeout: Duration(3 * time.Second),
+		},
+		Tracing: TracingConfig{
+			Exporter:    tracing.ExporterNone,
+			ServiceName: tracing.TracerName,
+			SampleRatio: 1,
+		},
+		Supabase: SupabaseConfig{
+			Timeout:    Duration(10 * time.Second),
+			MaxRetries: 2,
+		},
+		JWT: JWTConfig{
+			SigningAlg: "EdDSA",
+		},
+		R2: R2Config{
+			Bucket: "argumentum",
+		},
+		AccountDeletion: AccountDeletionConfig{
+			GraceDays: 30,
+		},
+	}
+}
+
+// Load reads CONFIG_FILE (when set), applies the environment on top and
+// validates the result.
+func Load() (*Config, error) {
+	cfg := Defaults()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+	cfg.resolve()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	str := func(key string, dst *string) {
+		if v := os.Getenv(key); v != "" {
+			*dst = v
+		}
+	}
+	var errs []error
+	duration := func(key string, dst *Duration) {
+		if v := os.Getenv(key); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = Duration(d)
+		}
+	}
+	list := func(key string, dst *[]string) {
+		if v := os.Getenv(key); v != "" {
+			var items []string
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			*dst = items
+		}
+	}
+	integer := func(key string, dst *int) {
+		if v := os.Getenv(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = n
+		}
+	}
+
+	float := func(key string, dst *float64) {
+		if v := os.Getenv(key); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = f
+		}
+	}
+
+	str("APP_ENV", &c.Env)
+	str("PORT", &c.Port)
+	str("LOG_LEVEL", &c.Log.Level)
+	str("LOG_FORMAT", &c.Log.Format)
+	duration("HTTP_READ_HEADER_TIMEOUT", &c.Server.ReadHeaderTimeout)
+	duration("HTTP_READ_TIMEOUT", &c.Server.ReadTimeout)
+	duration("HTTP_WRITE_TIMEOUT", &c.Server.WriteTimeout)
+	duration("HTTP_IDLE_TIMEOUT", &c.Server.IdleTimeout)
+	duration("HTTP_LONG_REQUEST_TIMEOUT", &c.Server.LongRequestTimeout)
+	duration("SHUTDOWN_PRE_STOP_DELAY", &c.Server.PreStopDelay)
+	duration("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+	list("TRUSTED_PROXIES", &c.Server.TrustedProxies)
+	list("CORS_ALLOWED_ORIGINS", &c.CORS.AllowedOrigins)
+	duration("CORS_MAX_AGE", &c.CORS.MaxAge)
+	duration("HSTS_MAX_AGE", &c.Security.HSTSMaxAge)
+	duration("HEALTH_CACHE_TTL", &c.Health.CacheTTL)
+	duration("HEALTH_CHECK_TIMEOUT", &c.Health.CheckTimeout)
+	str("METRICS_TOKEN", &c.Metrics.Token)
+	str("OTEL_TRACES_EXPORTER", &c.Tracing.Exporter)
+	str("OTEL_EXPORTER_OTLP_ENDPOINT", &c.Tracing.Endpoint)
+	str("OTEL_SERVICE_NAME", &c.Tracing.ServiceName)
+	float("OTEL_TRACES_SAMPLER_ARG", &c.Tracing.SampleRatio)
+
+	str("SUPABASE_URL", &c.Supabase.URL)
+	str("SUPABASE_SERVICE_ROLE_KEY", &c.Supabase.ServiceRoleKey)
+	duration("SUPABASE_TIMEOUT", &c.Supabase.Timeout)
+	integer("SUPABASE_MAX_RETRIES", &c.Supabase.MaxRetries)
+
+	str("DATA_STORE", &c.Store.Data)
+	str("AUTH_STORE", &c.Store.Auth)
+	str("SEED_FILE", &c.Store.SeedFile)
+
+	str("JWT_SIGNING_ALG", &c.JWT.SigningAlg)
+	str("JWT_KEYS_DIR", &c.JWT.KeysDir)
+	duration("JWT_KEY_ROTATION_INTERVAL", &c.JWT.RotationInterval)
+	str("JWT_SECRET", &c.JWT.LegacySecret)
+
+	str("MFA_ENCRYPTION_KEY", &c.MFA.EncryptionKey)
+
+	str("R2_ACCOUNT_ID", &c.R2.AccountID)
+	str("R2_ACCESS_KEY_ID", &c.R2.AccessKeyID)
+	str("R2_SECRET_ACCESS_KEY", &c.R2.SecretAccessKey)
+	str("R2_BUCKET_NAME", &c.R2.Bucket)
+
+	integer("ACCOUNT_DELETION_GRACE_DAYS", &c.AccountDeletion.GraceDays)
+
+	return errors.Join(errs...)
+}
+
+// resolve fills the settings that depend on others.
+func (c *Config) resolve() {
+	if c.Store.Data == "" {
+		c.Store.Data = StoreSupabase
+		if c.Supabase.ServiceRoleKey == "" {
+			c.Store.Data = StoreMemory
+		}
+	}
+	if c.Store.Auth == "" {
+		c.Store.Auth = c.Store.Data
+	}
+	if len(c.CORS.AllowedOrigins) == 0 && !c.IsProduction() {
+		c.CORS.AllowedOrigins = []string{"http://localhost:5173", "http://localhost:3000"}
+	}
+}
+
+// Validate reports every invalid setting at once. Production additionally
+// refuses ephemeral signing keys, the old default JWT secret, in-memory
+// stores and a missing service role or MFA key.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		fail("APP_ENV deve ser %q ou %q, recebido %q", EnvDevelopment, EnvProduction, c.Env)
+	}
+	if port, err := strconv.Atoi(c.Port); err != nil || port <= 0 || port > 65535 {
+		fail("PORT inválida: %q", c.Port)
+	}
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		fail("LOG_LEVEL deve ser debug, info, warn ou error, recebido %q", c.Log.Level)
+	}
+	if !logging.ValidFormat(c.Log.Format) {
+		fail("LOG_FORMAT deve ser %q ou %q, recebido %q", logging.FormatJSON, logging.FormatText, c.Log.Format)
+	}
+
+	for _, timeout := range []struct {
+		key   string
+		value Duration
+	}{
+		{"HTTP_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout},
+		{"HTTP_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"HTTP_LONG_REQUEST_TIMEOUT", c.Server.LongRequestTimeout},
+		{"SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+		{"HEALTH_CHECK_TIMEOUT", c.Health.CheckTimeout},
+	} {
+		if timeout.value <= 0 {
+			fail("%s deve ser positivo", timeout.key)
+		}
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				fail("TRUSTED_PROXIES: IP ou CIDR inválido %q", proxy)
+			}
+		}
+	}
+	if c.Server.PreStopDelay < 0 {
+		fail("SHUTDOWN_PRE_STOP_DELAY não pode ser negativo")
+	}
+	if c.Health.CacheTTL < 0 {
+		fail("HEALTH_CACHE_TTL não pode ser negativo")
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if !validOrigin(origin) {
+			fail("CORS_ALLOWED_ORIGINS: origem inválida %q (use https://app.exemplo.com ou https://*.exemplo.com)", origin)
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		fail("CORS_MAX_AGE não pode ser negativo")
+	}
+	if c.Security.HSTSMaxAge < 0 {
+		fail("HSTS_MAX_AGE não pode ser negativo")
+	}
+
+	if c.Tracing.Exporter != tracing.ExporterNone && c.Tracing.Exporter != tracing.ExporterOTLP {
+		fail("OTEL_TRACES_EXPORTER deve ser %q ou %q, recebido %q", tracing.ExporterNone, tracing.ExporterOTLP, c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("OTEL_TRACES_SAMPLER_ARG deve estar entre 0 e 1")
+	}
+
+	if c.Supabase.URL != "" {
+		if u, err := url.Parse(c.Supabase.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			fail("SUPABASE_URL inválida: %q", c.Supabase.URL)
+		}
+	}
+	if c.Supabase.Timeout <= 0 {
+		fail("SUPABASE_TIMEOUT deve ser positivo")
+	}
+	if c.Supabase.MaxRetries < 0 {
+		fail("SUPABASE_MAX_RETRIES não pode ser negativo")
+	}
+
+	for _, store := range []struct{ key, backend string }{
+		{"DATA_STORE", c.Store.Data},
+		{"AUTH_STORE", c.Store.Auth},
+	} {
+		key, backend := store.key, store.backend
+		switch backend {
+		case StoreMemory:
+		case StoreSupabase:
+			if c.Supabase.URL == "" || c.Supabase.ServiceRoleKey == "" {
+				fail("%s=supabase exige SUPABASE_URL e SUPABASE_SERVICE_ROLE_KEY", key)
+			}
+		default:
+			fail("%s deve ser %q ou %q, recebido %q", key, StoreSupabase, StoreMemory, backend)
+		}
+	}
+
+	if c.JWT.SigningAlg != "EdDSA" && c.JWT.SigningAlg != "RS256" {
+		fail("JWT_SIGNING_ALG deve ser EdDSA ou RS256, recebido %q", c.JWT.SigningAlg)
+	}
+	if c.JWT.RotationInterval < 0 {
+		fail("JWT_KEY_ROTATION_INTERVAL não pode ser negativo")
+	}
+	if c.AccountDeletion.GraceDays < 0 {
+		fail("ACCOUNT_DELETION_GRACE_DAYS não pode ser negativo")
+	}
+
+	if c.IsProduction() {
+		if c.JWT.KeysDir == "" {
+			fail("em produção JWT_KEYS_DIR é obrigatório (a chave efêmera invalida os tokens a cada reinício)")
+		}
+		if c.JWT.LegacySecret == defaultJWTSecret {
+			fail("em produção JWT_SECRET não pode ser o segredo padrão")
+		}
+		if c.Supabase.ServiceRoleKey == "" {
+			fail("em produção SUPABASE_SERVICE_ROLE_KEY é obrigatória")
+		}
+		if c.Store.Data == StoreMemory || c.Store.Auth == StoreMemory {
+			fail("em produção DATA_STORE e AUTH_STORE não podem ser %q", StoreMemory)
+		}
+		if c.MFA.EncryptionKey == "" {
+			fail("em produção MFA_ENCRYPTION_KEY é obrigatória")
+		}
+		if len(c.CORS.AllowedOrigins) == 0 {
+			fail("em produção CORS_ALLOWED_ORIGINS é obrigatório")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validOrigin accepts scheme://host[:port] with nothing after it, where the
+// host may start with a "*." label. The wildcard must leave at least two
+// labels (https://*.vercel.app, never https://*.app) and cannot stand alone:
+// credentials are allowed, so a catch-all would expose every session.
+func validOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.User != nil ||
+		u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.Hostname() == "" {
+		return false
+	}
+	host := strings.TrimPrefix(u.Hostname(), "*.")
+	if strings.Contains(host, "*") {
+		return false
+	}
+	return host == u.Hostname() || strings.Contains(host, ".")
+}
+
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+// Addr is the listen address for the HTTP server.
+func (c *Config) Addr() string {
+	return ":" + c.Port
+}
+
+// MemoryData reports whether the repositories run in memory.
+func (c *Config) MemoryData() bool {
+	return c.Store.Data == StoreMemory
+}
+
+// MemoryAuth reports whether sessions, revocations and API keys run in memory.
+func (c *Config) MemoryAuth() bool {
+	return c.Store.Auth == StoreMemory
+}
+
+// LogLevel is the minimum level written to the log.
+func (c *Config) LogLevel() slog.Level {
+	level, _ := logging.ParseLevel(c.Log.Level)
+	return level
+}
+
+// AccountDeletionGrace is how long a deletion request can be cancelled.
+func (c *Config) AccountDeletionGrace() time.Duration {
+	return time.Duration(c.AccountDeletion.GraceDays) * 24 * time.Hour
+}
@@ -0,0 +1,253 @@
+// Package gateway is the single way the backend talks to Supabase: PostgREST,
+// GoTrue and Storage share one HTTP client, per-call timeouts, retries on
+// transient failures and errors mapped onto models.ApiError.
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"argumentum-backend/internal/metrics"
+	"argumentum-backend/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrNotConfigured is returned by every call when SUPABASE_URL is not set,
+// e.g. when the API runs on the in-memory repositories.
+var ErrNotConfigured = errors.New("gateway: SUPABASE_URL is not set")
+
+const (
+	defaultTimeout     = 10 * time.Second
+	defaultBaseBackoff = 200 * time.Millisecond
+	maxBackoff         = 5 * time.Second
+)
+
+// Config configures a Client. Zero values get sensible defaults, except
+// MaxRetries: zero disables retries.
+type Config struct {
+	URL        string
+	ServiceKey string
+	// Timeout bounds each attempt of a call.
+	Timeout time.Duration
+	// MaxRetries is how many times a transient failure is retried.
+	MaxRetries  int
+	BaseBackoff time.Duration
+	HTTPClient  *http.Client
+}
+
+// Client is safe for concurrent use and meant to be shared.
+type Client struct {
+	url         string
+	serviceKey  string
+	timeout     time.Duration
+	maxRetries  int
+	baseBackoff time.Duration
+	http        *http.Client
+}
+
+func New(cfg Config) *Client {
+	c := &Client{
+		url:         cfg.URL,
+		serviceKey:  cfg.ServiceKey,
+		timeout:     cfg.Timeout,
+		maxRetries:  cfg.MaxRetries,
+		baseBackoff: cfg.BaseBackoff,
+		http:        cfg.HTTPClient,
+	}
+	if c.timeout <= 0 {
+		c.timeout = defaultTimeout
+	}
+	if c.maxRetries < 0 {
+		c.maxRetries = 0
+	}
+	if c.baseBackoff <= 0 {
+		c.baseBackoff = defaultBaseBackoff
+	}
+	if c.http == nil {
+		c.http = &http.Client{Transport: tracing.Transport(nil)}
+	}
+	return c
+}
+
+// request is one call to a Supabase service.
+type request struct {
+	method  string
+	path    string
+	prefer  string
+	payload interface{}
+	result  interface{}
+	// once disables retries for this call.
+	once bool
+	// count, when set, receives the total of a Prefer: count=exact select.
+	count *int
+}
+
+// do sends the request, retrying transient failures, and decodes the JSON
+// response into result when set.
+func (c *Client) do(ctx context.Context, r request) (err error) {
+	if c.url == "" {
+		return ErrNotConfigured
+	}
+	ctx, span := startSpan(ctx, r.method, r.path)
+	defer func() { endSpan(span, err) }()
+	var body []byte
+	if r.payload != nil {
+		var err error
+		if body, err = json.Marshal(r.payload); err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		respBytes, status, header, err := c.attempt(ctx, r, body)
+		if err == nil && status >= 200 && status < 300 {
+			if r.count != nil {
+				if *r.count, err = parseContentRangeTotal(header.Get("Content-Range")); err != nil {
+					return err
+				}
+			}
+			if r.result != nil && len(respBytes) > 0 {
+				return json.Unmarshal(respBytes, r.result)
+			}
+			return nil
+		}
+		// PostgREST answers a page past the end of a counted select with
+		// 416; the page is just empty.
+		if err == nil && r.count != nil && status == http.StatusRequestedRangeNotSatisfiable {
+			if total, rangeErr := parseContentRangeTotal(header.Get("Content-Range")); rangeErr == nil {
+				*r.count = total
+				return nil
+			}
+		}
+		if err == nil {
+			err = newError(status, respBytes)
+		}
+
+		if r.once || attempt >= c.maxRetries || !retryable(r.method, status, err) || ctx.Err() != nil {
+			return err
+		}
+		wait := c.backoff(attempt, parseRetryAfter(header.Get("Retry-After")))
+		metrics.UpstreamRetry(metrics.SupabaseService(r.path))
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt+1),
+			attribute.Int("http.response.status_code", status),
+		))
+		slog.WarnContext(ctx, "Supabase call failed, retrying", "method", r.method, "path", r.path, "error", err, "retry_in", wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// attempt performs a single HTTP round trip bounded by the per-call timeout.
+func (c *Client) attempt(ctx context.Context, r request, body []byte) ([]byte, int, http.Header, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, c.url+r.path, reader)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	c.authorize(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if r.prefer != "" {
+		req.Header.Set("Prefer", r.prefer)
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		metrics.ObserveUpstream(metrics.SupabaseService(r.path), r.method, 0, time.Since(start))
+		return nil, 0, nil, err
+	}
+	defer resp.Body.Close()
+	metrics.ObserveUpstream(metrics.SupabaseService(r.path), r.method, resp.StatusCode, time.Since(start))
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return respBytes, resp.StatusCode, resp.Header, nil
+}
+
+func (c *Client) authorize(req *http.Request) {
+	req.Header.Set("apikey", c.serviceKey)
+	req.Header.Set("Authorization", "Bearer "+c.serviceKey)
+}
+
+// retryable decides whether a failed call may be sent again. POST is not
+// idempotent, so it is only retried when the server refused it outright (429).
+func retryable(method string, status int, err error) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	if method == http.MethodPost {
+		return false
+	}
+	if status >= 500 {
+		return true
+	}
+	// Network failures and per-attempt timeouts carry no status.
+	return status == 0 && !errors.Is(err, context.Canceled)
+}
+
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if retryAfter > maxBackoff {
+			return maxBackoff
+		}
+		return retryAfter
+	}
+	wait := c.baseBackoff << attempt
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+	// Jitter keeps instances from retrying in lockstep.
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// parseContentRangeTotal reads the total of a PostgREST Content-Range such as
+// "0-19/57", or "*/0" when nothing matched.
+func parseContentRangeTotal(value string) (int, error) {
+	i := strings.LastIndexByte(value, '/')
+	if i < 0 {
+		return 0, fmt.Errorf("gateway: no total in Content-Range %q", value)
+	}
+	total, err := strconv.Atoi(value[i+1:])
+	if err != nil {
+		return 0, fmt.Errorf("gateway: no total in Content-Range %q", value)
+	}
+	return total, nil
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,187 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"argumentum-backend/models"
+)
+
+// newTestClient points a Client at handler with fast retries.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return New(Config{URL: srv.URL, ServiceKey: "key", MaxRetries: 2, BaseBackoff: time.Millisecond})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		status int
+		want   int32 // attempts
+	}{
+		{"GET on 503", http.MethodGet, http.StatusServiceUnavailable, 3},
+		{"PATCH on 502", http.MethodPatch, http.StatusBadGateway, 3},
+		{"POST on 500", http.MethodPost, http.StatusInternalServerError, 1},
+		{"POST on 429", http.MethodPost, http.StatusTooManyRequests, 3},
+		{"GET on 400", http.MethodGet, http.StatusBadRequest, 1},
+		{"DELETE on 404", http.MethodDelete, http.StatusNotFound, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tc.status)
+			})
+			err := c.do(context.Background(), request{method: tc.method, path: "/rest/v1/t"})
+			if !hasStatus(err, tc.status) {
+				t.Fatalf("err = %v, want status %d", err, tc.status)
+			}
+			if attempts != tc.want {
+				t.Fatalf("attempts = %d, want %d", attempts, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetryRecoversAndDecodes(t *testing.T) {
+	var attempts int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[{"id":"a"}]`))
+	})
+	var rows []struct{ ID string }
+	if err := c.Select(context.Background(), "t", NewQuery(), &rows); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 || len(rows) != 1 || rows[0].ID != "a" {
+		t.Fatalf("attempts = %d, rows = %+v", attempts, rows)
+	}
+}
+
+func TestRetryableNetworkErrors(t *testing.T) {
+	timeout := context.DeadlineExceeded
+	if !retryable(http.MethodPatch, 0, timeout) {
+		t.Error("PATCH timeout should be retried")
+	}
+	if retryable(http.MethodPost, 0, timeout) {
+		t.Error("POST timeout must not be retried")
+	}
+	if retryable(http.MethodGet, 0, context.Canceled) {
+		t.Error("cancelled call must not be retried")
+	}
+}
+
+func TestBackoffHonoursRetryAfter(t *testing.T) {
+	c := New(Config{URL: "http://x", BaseBackoff: 100 * time.Millisecond})
+	if got := c.backoff(0, 2*time.Second); got != 2*time.Second {
+		t.Errorf("backoff with Retry-After 2s = %v", got)
+	}
+	if got := c.backoff(0, time.Minute); got != maxBackoff {
+		t.Errorf("backoff with Retry-After 1m = %v, want cap %v", got, maxBackoff)
+	}
+	for attempt := 0; attempt < 10; attempt++ {
+		if got := c.backoff(attempt, 0); got <= 0 || got > maxBackoff {
+			t.Errorf("backoff(%d) = %v", attempt, got)
+		}
+	}
+	if got := parseRetryAfter("3"); got != 3*time.Second {
+		t.Errorf("parseRetryAfter(3) = %v", got)
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	long := make([]byte, maxRawMessage+100)
+	for i := range long {
+		long[i] = 'x'
+	}
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		want   models.ApiError
+	}{
+		{"postgrest", 409, `{"code":"23505","message":"duplicate key"}`, models.ApiError{Status: 409, Code: "23505", Message: "duplicate key"}},
+		{"gotrue", 422, `{"error_code":"weak_password","msg":"too short"}`, models.ApiError{Status: 422, Code: "weak_password", Message: "too short"}},
+		{"oauth", 400, `{"error":"invalid_grant","error_description":"bad creds"}`, models.ApiError{Status: 400, Code: "invalid_grant", Message: "bad creds"}},
+		{"empty", 503, ``, models.ApiError{Status: 503, Message: "Service Unavailable"}},
+		{"not json", 502, `<html>bad gateway</html>`, models.ApiError{Status: 502, Message: "<html>bad gateway</html>"}},
+		{"truncated", 500, string(long), models.ApiError{Status: 500, Message: string(long[:maxRawMessage])}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := newError(tc.status, []byte(tc.body)); *got != tc.want {
+				t.Fatalf("newError = %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+
+	if !IsConflict(newError(400, []byte(`{"code":"23503"}`))) {
+		t.Error("foreign key violation should be a conflict")
+	}
+	if !IsNotFound(errors.Join(errors.New("ctx"), newError(404, nil))) {
+		t.Error("wrapped 404 should be found")
+	}
+}
+
+func TestUpdateOnceIsNotRetried(t *testing.T) {
+	var attempts int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	err := c.UpdateOnce(context.Background(), "t", NewQuery().IsNull("used_at"), map[string]string{}, nil)
+	if !hasStatus(err, http.StatusBadGateway) || attempts != 1 {
+		t.Fatalf("err = %v after %d attempts, want one 502", err, attempts)
+	}
+}
+
+func TestSelectCount(t *testing.T) {
+	cases := []struct {
+		name         string
+		status       int
+		contentRange string
+		body         string
+		wantTotal    int
+		wantRows     int
+		wantErr      bool
+	}{
+		{"page", http.StatusOK, "0-1/57", `[{"id":"a"},{"id":"b"}]`, 57, 2, false},
+		{"nothing matches", http.StatusOK, "*/0", `[]`, 0, 0, false},
+		{"page past the end", http.StatusRequestedRangeNotSatisfiable, "*/3", `{"code":"PGRST103"}`, 3, 0, false},
+		{"missing header", http.StatusOK, "", `[]`, 0, 0, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Prefer") != "count=exact" {
+					t.Errorf("Prefer = %q", r.Header.Get("Prefer"))
+				}
+				if tc.contentRange != "" {
+					w.Header().Set("Content-Range", tc.contentRange)
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			})
+			rows := []struct{ ID string }{}
+			total, err := c.SelectCount(context.Background(), "t", NewQuery().Limit(2), &rows)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && (total != tc.wantTotal || len(rows) != tc.wantRows) {
+				t.Fatalf("total = %d, rows = %d, want %d and %d", total, len(rows), tc.wantTotal, tc.wantRows)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"argumentum-backend/models"
+)
+
+// maxRawMessage caps how much of an unrecognised error body is kept, so a
+// full response never ends up in a log line.
+const maxRawMessage = 256
+
+// newError maps a PostgREST, GoTrue or Storage error body onto
+// models.ApiError. Each service names its fields differently; the start of
+// the raw body is kept as the message when none of them is present.
+func newError(status int, body []byte) *models.ApiError {
+	var payload struct {
+		// PostgREST
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		// GoTrue
+		ErrorCode        string `json:"error_code"`
+		Msg              string `json:"msg"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	raw := body
+	if len(raw) > maxRawMessage {
+		raw = raw[:maxRawMessage]
+	}
+	apiErr := &models.ApiError{Status: status, Message: string(raw)}
+	if len(body) == 0 {
+		apiErr.Message = http.StatusText(status)
+		return apiErr
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return apiErr
+	}
+
+	apiErr.Code = firstNonEmpty(payload.Code, payload.ErrorCode, payload.Error)
+	if message := firstNonEmpty(payload.Message, payload.Msg, payload.ErrorDescription, payload.Error); message != "" {
+		apiErr.Message = message
+	}
+	return apiErr
+}
+
+// IsNotFound reports whether err is a 404 from Supabase.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is a unique or foreign key violation.
+func IsConflict(err error) bool {
+	var apiErr *models.ApiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Status == http.StatusConflict || apiErr.Code == "23505" || apiErr.Code == "23503"
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *models.ApiError
+	return errors.As(err, &apiErr) && apiErr.Status == status
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+)
+
+// PingAuth checks that GoTrue answers its health endpoint.
+func (c *Client) PingAuth(ctx context.Context) error {
+	return c.ping(ctx, http.MethodGet, "/auth/v1/health")
+}
+
+// PingREST checks that PostgREST answers and accepts the service role key.
+func (c *Client) PingREST(ctx context.Context) error {
+	return c.ping(ctx, http.MethodHead, "/rest/v1/profiles?select=id&limit=1")
+}
+
+// PingStorage checks that the Storage API answers and accepts the key.
+func (c *Client) PingStorage(ctx context.Context) error {
+	return c.ping(ctx, http.MethodGet, "/storage/v1/bucket")
+}
+
+// ping makes a single attempt, without retries: a probe must report the
+// current state, not wait it out.
+func (c *Client) ping(ctx context.Context, method, path string) error {
+	if c.url == "" {
+		return ErrNotConfigured
+	}
+	body, status, _, err := c.attempt(ctx, request{method: method, path: path}, nil)
+	if err != nil {
+		return err
+	}
+	if status < 200 || status >= 300 {
+		return newError(status, body)
+	}
+	return nil
+}
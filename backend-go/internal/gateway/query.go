@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Query builds PostgREST query strings. Values are escaped, so user input can
+// be passed to filters as is.
+type Query struct {
+	values url.Values
+}
+
+// NewQuery starts an empty query.
+func NewQuery() *Query {
+	return &Query{values: url.Values{}}
+}
+
+// Select restricts the returned columns.
+func (q *Query) Select(columns ...string) *Query {
+	q.values.Set("select", strings.Join(columns, ","))
+	return q
+}
+
+func (q *Query) Eq(column, value string) *Query  { return q.filter(column, "eq", value) }
+func (q *Query) Neq(column, value string) *Query { return q.filter(column, "neq", value) }
+func (q *Query) Gt(column, value string) *Query  { return q.filter(column, "gt", value) }
+func (q *Query) Gte(column, value string) *Query { return q.filter(column, "gte", value) }
+func (q *Query) Lt(column, value string) *Query  { return q.filter(column, "lt", value) }
+func (q *Query) Lte(column, value string) *Query { return q.filter(column, "lte", value) }
+
+// ILike matches column against a pattern using * as the wildcard.
+func (q *Query) ILike(column, pattern string) *Query { return q.filter(column, "ilike", pattern) }
+
+// IsNull and NotNull filter on NULL columns.
+func (q *Query) IsNull(column string) *Query  { return q.filter(column, "is", "null") }
+func (q *Query) NotNull(column string) *Query { return q.filter(column, "not.is", "null") }
+
+// In matches any of values. Each value is quoted, so commas and parentheses
+// inside values cannot alter the list.
+func (q *Query) In(column string, values ...string) *Query {
+	return q.filter(column, "in", quoteList(values))
+}
+
+// Or matches rows satisfying any of conditions, built with Cond and CondIn.
+func (q *Query) Or(conditions ...string) *Query {
+	q.values.Add("or", "("+strings.Join(conditions, ",")+")")
+	return q
+}
+
+// Cond is a condition of Or such as Cond("user_id", "eq", id). The value is
+// quoted, as in In.
+func Cond(column, op, value string) string {
+	return column + "." + op + "." + quote(value)
+}
+
+// CondIn is an in condition of Or.
+func CondIn(column string, values ...string) string {
+	return column + ".in." + quoteList(values)
+}
+
+// Order sorts by column, ascending unless desc.
+func (q *Query) Order(column string, desc bool) *Query {
+	dir := "asc"
+	if desc {
+		dir = "desc"
+	}
+	q.values.Add("order", column+"."+dir)
+	return q
+}
+
+func (q *Query) Limit(n int) *Query {
+	q.values.Set("limit", strconv.Itoa(n))
+	return q
+}
+
+func (q *Query) Offset(n int) *Query {
+	q.values.Set("offset", strconv.Itoa(n))
+	return q
+}
+
+// Encode returns the query string, without the leading "?".
+func (q *Query) Encode() string {
+	if q == nil {
+		return ""
+	}
+	return q.values.Encode()
+}
+
+func (q *Query) filter(column, op, value string) *Query {
+	q.values.Add(column, op+"."+value)
+	return q
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote(v)
+	}
+	return "(" + strings.Join(quoted, ",") + ")"
+}
+
+// quote wraps a value in double quotes, PostgREST's way of escaping reserved
+// characters inside lists.
+func quote(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return `"` + v + `"`
+}
@@ -0,0 +1,44 @@
+package gateway
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestQueryEscaping(t *testing.T) {
+	cases := []struct {
+		name  string
+		query *Query
+		key   string
+		want  string
+	}{
+		{"eq keeps reserved chars in the value", NewQuery().Eq("email", "a&b=c@x.com"), "email", "eq.a&b=c@x.com"},
+		{"in quotes each value", NewQuery().In("id", `a,b`, `c)`), "id", `in.("a,b","c)")`},
+		{"quotes and backslashes", NewQuery().In("name", `say "hi"\`), "name", `in.("say \"hi\"\\")`},
+		{"or", NewQuery().Or(Cond("user_id", "eq", "u,1"), CondIn("team_id", "t1", "t2")), "or", `(user_id.eq."u,1",team_id.in.("t1","t2"))`},
+		{"is null", NewQuery().IsNull("used_at"), "used_at", "is.null"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tc.query.Encode())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := values.Get(tc.key); got != tc.want {
+				t.Fatalf("%s = %q, want %q", tc.key, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQueryPaging(t *testing.T) {
+	got := NewQuery().Select("id").Order("created_at", true).Limit(10).Offset(20).Encode()
+	want := "limit=10&offset=20&order=created_at.desc&select=id"
+	if got != want {
+		t.Fatalf("Encode = %q, want %q", got, want)
+	}
+	var nilQuery *Query
+	if nilQuery.Encode() != "" {
+		t.Fatal("nil query should encode empty")
+	}
+}
@@ -0,0 +1,150 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"argumentum-backend/internal/metrics"
+)
+
+func restPath(resource string, q *Query) string {
+	path := "/rest/v1/" + resource
+	if encoded := q.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+	return path
+}
+
+// Select reads the rows of resource matching q into result (a slice).
+func (c *Client) Select(ctx context.Context, resource string, q *Query, result interface{}) error {
+	return c.do(ctx, request{method: http.MethodGet, path: restPath(resource, q), result: result})
+}
+
+// SelectCount is Select that also returns how many rows match q in total,
+// regardless of its limit and offset.
+func (c *Client) SelectCount(ctx context.Context, resource string, q *Query, result interface{}) (int, error) {
+	var total int
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   restPath(resource, q),
+		prefer: "count=exact",
+		result: result,
+		count:  &total,
+	})
+	return total, err
+}
+
+// Insert creates rows; when result is set it receives the created rows.
+func (c *Client) Insert(ctx context.Context, resource string, payload, result interface{}) error {
+	return c.do(ctx, request{
+		method:  http.MethodPost,
+		path:    restPath(resource, nil),
+		prefer:  "return=representation",
+		payload: payload,
+		result:  result,
+	})
+}
+
+// Upsert inserts rows, merging on the table's primary key.
+func (c *Client) Upsert(ctx context.Context, resource string, payload interface{}) error {
+	return c.do(ctx, request{
+		method:  http.MethodPost,
+		path:    restPath(resource, nil),
+		prefer:  "resolution=merge-duplicates,return=minimal",
+		payload: payload,
+	})
+}
+
+// Update patches the rows matching q. result, when set, receives the updated
+// rows, which tells conditional updates that matched nothing apart.
+func (c *Client) Update(ctx context.Context, resource string, q *Query, payload, result interface{}) error {
+	return c.do(ctx, request{
+		method:  http.MethodPatch,
+		path:    restPath(resource, q),
+		prefer:  "return=representation",
+		payload: payload,
+		result:  result,
+	})
+}
+
+// UpdateOnce is Update without retries, for conditional updates whose retry
+// cannot be told apart from a lost race: when the first attempt did land, the
+// retry matches nothing.
+func (c *Client) UpdateOnce(ctx context.Context, resource string, q *Query, payload, result interface{}) error {
+	return c.do(ctx, request{
+		method:  http.MethodPatch,
+		path:    restPath(resource, q),
+		prefer:  "return=representation",
+		payload: payload,
+		result:  result,
+		once:    true,
+	})
+}
+
+// Delete removes the rows matching q.
+func (c *Client) Delete(ctx context.Context, resource string, q *Query) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: restPath(resource, q)})
+}
+
+// RPC calls a Postgres function exposed by PostgREST.
+func (c *Client) RPC(ctx context.Context, function string, args, result interface{}) error {
+	return c.do(ctx, request{
+		method:  http.MethodPost,
+		path:    "/rest/v1/rpc/" + function,
+		payload: args,
+		result:  result,
+	})
+}
+
+// Function invokes a Supabase Edge Function with the service role key. Like
+// every call, it carries the W3C traceparent header so the function can join
+// the trace.
+func (c *Client) Function(ctx context.Context, name string, payload, result interface{}) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/functions/v1/" + name, payload: payload, result: result})
+}
+
+// Auth calls the GoTrue API; path is relative to /auth/v1, e.g.
+// "/token?grant_type=password".
+func (c *Client) Auth(ctx context.Context, method, path string, payload, result interface{}) error {
+	return c.do(ctx, request{method: method, path: "/auth/v1" + path, payload: payload, result: result})
+}
+
+// AdminUserPath is the GoTrue admin path for a user, for use with Auth.
+func AdminUserPath(userID string) string {
+	return "/admin/users/" + url.PathEscape(userID)
+}
+
+// StorageObject streams an object from Supabase Storage. The caller closes
+// the reader. Only the request is bounded by the timeout, not the read.
+func (c *Client) StorageObject(ctx context.Context, bucket, path string) (_ io.ReadCloser, err error) {
+	if c.url == "" {
+		return nil, ErrNotConfigured
+	}
+	ctx, span := startSpan(ctx, http.MethodGet, "/storage/v1/object")
+	defer func() { endSpan(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/storage/v1/object/"+url.PathEscape(bucket)+"/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.authorize(req)
+
+	// Only the time to the response headers is measured; the body streams
+	// into the caller.
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		metrics.ObserveUpstream("storage", http.MethodGet, 0, time.Since(start))
+		return nil, err
+	}
+	metrics.ObserveUpstream("storage", http.MethodGet, resp.StatusCode, time.Since(start))
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, newError(resp.StatusCode, body)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,53 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+
+	"argumentum-backend/internal/metrics"
+	"argumentum-backend/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan opens the span covering one logical call, retries included. Each
+// attempt gets its own child span from the traced transport.
+func startSpan(ctx context.Context, method, path string) (context.Context, trace.Span) {
+	service := metrics.SupabaseService(path)
+	operation := operationName(path)
+	return tracing.Tracer().Start(ctx, "supabase."+service+" "+method+" "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("supabase.service", service),
+			attribute.String("supabase.operation", operation),
+			attribute.String("http.request.method", method),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// operationName keeps span names low-cardinality: the table or function for
+// PostgREST, the first path segment for GoTrue, Storage and Edge Functions
+// (IDs and query strings are dropped).
+func operationName(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	for _, prefix := range []string{"/rest/v1/", "/auth/v1/", "/storage/v1/", "/functions/v1/"} {
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			segments := strings.SplitN(rest, "/", 3)
+			if prefix == "/rest/v1/" && segments[0] == "rpc" && len(segments) > 1 {
+				return "rpc/" + segments[1]
+			}
+			return segments[0]
+		}
+	}
+	return path
+}
@@ -0,0 +1,115 @@
+// Package health runs the readiness checks of the upstream services and
+// caches their results, so frequent probes do not hammer Supabase.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Check reports whether a dependency is usable.
+type Check func(ctx context.Context) error
+
+// Result is the outcome of one check.
+type Result struct {
+	Status    string    `json:"status"`
+	LatencyMs int64     `json:"latencyMs"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs the registered checks in parallel, each bounded by timeout,
+// and reuses results younger than ttl.
+type Checker struct {
+	ttl     time.Duration
+	timeout time.Duration
+	checks  []namedCheck
+
+	// refresh serializes refreshes; mu guards cached.
+	refresh sync.Mutex
+	mu      sync.Mutex
+	cached  map[string]Result
+}
+
+func NewChecker(ttl, timeout time.Duration) *Checker {
+	return &Checker{ttl: ttl, timeout: timeout, cached: make(map[string]Result)}
+}
+
+// Register adds a check. It must be called before the first Run.
+func (c *Checker) Register(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Run returns the result of every check and whether all of them are up.
+func (c *Checker) Run(ctx context.Context) (map[string]Result, bool) {
+	c.refresh.Lock()
+	defer c.refresh.Unlock()
+
+	var wg sync.WaitGroup
+	for _, nc := range c.checks {
+		if c.fresh(nc.name) {
+			continue
+		}
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+			c.store(nc.name, c.run(ctx, nc))
+		}(nc)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	results := make(map[string]Result, len(c.cached))
+	ready := true
+	for name, result := range c.cached {
+		results[name] = result
+		if result.Status != StatusUp {
+			ready = false
+		}
+	}
+	return results, ready
+}
+
+func (c *Checker) run(ctx context.Context, nc namedCheck) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := nc.check(ctx)
+	result := Result{
+		Status:    StatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+		CheckedAt: time.Now().UTC(),
+	}
+	if err != nil {
+		// Details stay in the log; the probe response is public.
+		slog.WarnContext(ctx, "Readiness check failed", "check", nc.name, "error", err)
+		result.Status = StatusDown
+	}
+	return result
+}
+
+func (c *Checker) fresh(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.cached[name]
+	return ok && time.Since(result.CheckedAt) < c.ttl
+}
+
+func (c *Checker) store(name string, result Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cached[name] = result
+}
@@ -0,0 +1,297 @@
+package i18n
+
+import "argumentum-backend/models"
+
+// Keys of the success messages sent in ApiResponse.Message.
+const (
+	MsgLoggedOut         = "logged_out"
+	MsgAllSessionsEnded  = "all_sessions_ended"
+	MsgSessionEnded      = "session_ended"
+	MsgResetEmailSent    = "reset_email_sent"
+	MsgPasswordReset     = "password_reset"
+	MsgPasswordChanged   = "password_changed"
+	MsgMFAEnabled        = "mfa_enabled"
+	MsgMFADisabled       = "mfa_disabled"
+	MsgDeletionScheduled = "deletion_scheduled"
+	MsgDeletionCancelled = "deletion_cancelled"
+	MsgExportStarted     = "export_started"
+	MsgAPIKeyCreated     = "api_key_created"
+	MsgAPIKeyRevoked     = "api_key_revoked"
+	MsgPetitionDeleted   = "petition_deleted"
+)
+
+// Keys of the messages in models.FieldError. min and max depend on what is
+// measured: characters, items or a number.
+const (
+	FieldRequired  = "field.required"
+	FieldEmail     = "field.email"
+	FieldMinString = "field.min.string"
+	FieldMinItems  = "field.min.items"
+	FieldMinNumber = "field.min.number"
+	FieldMaxString = "field.max.string"
+	FieldMaxItems  = "field.max.items"
+	FieldMaxNumber = "field.max.number"
+	FieldType      = "field.type"
+	FieldInvalid   = "field.invalid"
+)
+
+// Keys of the device labels in the session list.
+const (
+	DeviceUnknown     = "device.unknown"
+	DeviceBrowserOnOS = "device.browser_on_os"
+)
+
+var catalog = map[string]map[string]string{
+	PtBR: {
+		models.ErrCodeInternal:         "Erro interno do servidor",
+		models.ErrCodeInvalidJSON:      "Corpo da requisição ausente ou com JSON inválido",
+		models.ErrCodeValidationFailed: "Dados inválidos",
+		models.ErrCodeInvalidQuery:     "Parâmetros de consulta inválidos",
+		models.ErrCodeNotImplemented:   "Endpoint ainda não implementado",
+		models.ErrCodeTooManyAttempts:  "Muitas tentativas. Tente novamente em %d segundos",
+		models.ErrCodeRateLimited:      "Limite de requisições excedido. Tente novamente em %d segundos",
+		models.ErrCodeRequestTooLarge:  "Corpo da requisição grande demais",
+
+		models.ErrCodeInvalidIdempotencyKey: "Idempotency-Key deve ter no máximo %d caracteres",
+		models.ErrCodeIdempotencyKeyReused:  "Esta Idempotency-Key já foi usada com outro corpo",
+		models.ErrCodeIdempotencyInProgress: "Uma requisição com esta Idempotency-Key ainda está em andamento",
+
+		models.ErrCodeMissingToken:        "Token de autorização necessário",
+		models.ErrCodeInvalidTokenFormat:  "Formato de token inválido",
+		models.ErrCodeInvalidToken:        "Token inválido",
+		models.ErrCodeTokenRevoked:        "Token revogado",
+		models.ErrCodeInvalidAPIKey:       "Chave de API inválida",
+		models.ErrCodeUnauthenticated:     "Usuário não autenticado",
+		models.ErrCodeInvalidCredentials:  "Email ou senha incorretos",
+		models.ErrCodeInvalidRefreshToken: "Token de refresh inválido",
+		models.ErrCodeSessionExpired:      "Sessão expirada, faça login novamente",
+		models.ErrCodeSessionRevoked:      "Sessão revogada, faça login novamente",
+		models.ErrCodeSessionNotFound:     "Sessão não encontrada",
+		models.ErrCodeSessionsFetchFailed: "Erro ao buscar sessões",
+		models.ErrCodeSessionEndFailed:    "Erro ao encerrar sessão",
+		models.ErrCodeSessionsEndFailed:   "Erro ao encerrar sessões",
+
+		models.ErrCodeAdminRequired:     "Acesso restrito a administradores",
+		models.ErrCodeTeamAccessDenied:  "Sem permissão para acessar esta equipe",
+		models.ErrCodeAPIKeyScopeDenied: "Chave de API sem permissão para esta operação",
+
+		models.ErrCodeTermsNotAccepted:       "É necessário aceitar os termos de uso",
+		models.ErrCodeEmailAlreadyRegistered: "Já existe uma conta com este email",
+		models.ErrCodeWeakPassword:           "Senha muito fraca",
+		models.ErrCodeRegistrationFailed:     "Erro ao criar conta",
+		models.ErrCodeInviteNotFound:         "Convite não encontrado",
+		models.ErrCodeInviteNotPending:       "Este convite já foi utilizado ou cancelado",
+		models.ErrCodeInviteExpired:          "Este convite expirou",
+		models.ErrCodeInviteEmailMismatch:    "Este convite foi enviado para outro email",
+		models.ErrCodeInviteAcceptFailed:     "Erro ao aceitar convite",
+
+		models.ErrCodeIncorrectPassword:        "Senha incorreta",
+		models.ErrCodeIncorrectCurrentPassword: "Senha atual incorreta",
+		models.ErrCodeInvalidRecoveryLink:      "Link de recuperação inválido ou expirado",
+		models.ErrCodePasswordResetFailed:      "Erro ao redefinir senha",
+		models.ErrCodePasswordChangeFailed:     "Erro ao alterar senha",
+		models.ErrCodeSessionsNotEnded:         "Senha alterada, mas houve erro ao encerrar as sessões",
+
+		models.ErrCodeMFAAlreadyEnabled:     "Autenticação em dois fatores já está ativa",
+		models.ErrCodeMFANotEnabled:         "Autenticação em dois fatores não está ativa",
+		models.ErrCodeMFAUnavailable:        "Autenticação em dois fatores indisponível",
+		models.ErrCodeMFAEnrollmentNotFound: "Nenhum cadastro de autenticação em dois fatores pendente",
+		models.ErrCodeInvalidMFACode:        "Código inválido",
+		models.ErrCodeInvalidMFAToken:       "Token de verificação inválido ou expirado",
+		models.ErrCodeInvalidRecoveryCode:   "Código de recuperação inválido",
+
+		models.ErrCodeProfileNotFound:          "Perfil não encontrado",
+		models.ErrCodeProfileFetchFailed:       "Erro ao buscar perfil do usuário",
+		models.ErrCodeDeletionAlreadyRequested: "Já existe um pedido de exclusão em andamento",
+		models.ErrCodeTeamOwnershipPending:     "Transfira a propriedade das suas equipes antes de excluir a conta",
+		models.ErrCodeDeletionRequestFailed:    "Erro ao registrar pedido de exclusão",
+		models.ErrCodeDeletionCancelFailed:     "Erro ao cancelar pedido de exclusão",
+		models.ErrCodeDeletionInProgress:       "A exclusão da conta já está em execução e não pode mais ser cancelada",
+		models.ErrCodeDeletionNotFound:         "Pedido de exclusão não encontrado",
+		models.ErrCodeNoPendingDeletion:        "Nenhum pedido de exclusão em andamento",
+		models.ErrCodeExportInProgress:         "Já existe uma exportação em andamento",
+		models.ErrCodeExportStartFailed:        "Erro ao iniciar exportação",
+		models.ErrCodeExportNotFound:           "Exportação não encontrada",
+		models.ErrCodeExportUnavailable:        "Exportação de dados indisponível no momento",
+		models.ErrCodeInvalidDownloadLink:      "Link de download inválido ou expirado",
+
+		models.ErrCodePetitionNotFound:        "Petição não encontrada",
+		models.ErrCodePetitionAccessDenied:    "Sem permissão para acessar esta petição",
+		models.ErrCodePetitionDeleteForbidden: "Apenas o autor da petição ou um administrador pode excluí-la",
+		models.ErrCodePetitionsFetchFailed:    "Erro ao buscar petições",
+		models.ErrCodePetitionCreateFailed:    "Erro ao criar petição",
+		models.ErrCodePetitionUpdateFailed:    "Erro ao atualizar petição",
+		models.ErrCodePetitionDeleteFailed:    "Erro ao excluir petição",
+		models.ErrCodeInsufficientTokens:      "Saldo de tokens insuficiente: criar uma petição custa %d tokens",
+
+		models.ErrCodeTeamIDRequired:          "ID da equipe é obrigatório",
+		models.ErrCodeTeamOwnerFetchFailed:    "Erro ao buscar proprietário da equipe",
+		models.ErrCodeTeamOwnerNotFound:       "Proprietário da equipe não encontrado",
+		models.ErrCodeTokenBalanceFetchFailed: "Erro ao buscar saldo de tokens",
+
+		models.ErrCodeAPIKeysFetchFailed: "Erro ao buscar chaves de API",
+		models.ErrCodeInvalidScopes:      "Permissões inválidas. Valores aceitos: %s",
+		models.ErrCodeTeamKeyForbidden:   "Sem permissão para criar chaves para esta equipe",
+		models.ErrCodeAPIKeyCreateFailed: "Erro ao criar chave de API",
+		models.ErrCodeAPIKeyUpdateFailed: "Erro ao atualizar chave de API",
+		models.ErrCodeAPIKeyRevokeFailed: "Erro ao revogar chave de API",
+		models.ErrCodeAPIKeyNotFound:     "Chave de API não encontrada",
+
+		models.ErrCodeStatsFetchFailed: "Erro ao buscar estatísticas",
+
+		MsgLoggedOut:         "Logout realizado com sucesso",
+		MsgAllSessionsEnded:  "Todas as sessões foram encerradas",
+		MsgSessionEnded:      "Sessão encerrada",
+		MsgResetEmailSent:    "Se o email existir, você receberá instruções para redefinir sua senha",
+		MsgPasswordReset:     "Senha redefinida com sucesso. Faça login novamente",
+		MsgPasswordChanged:   "Senha alterada com sucesso",
+		MsgMFAEnabled:        "Autenticação em dois fatores ativada",
+		MsgMFADisabled:       "Autenticação em dois fatores desativada",
+		MsgDeletionScheduled: "Exclusão agendada. Você pode cancelar até a data prevista; guarde o receiptToken para obter o comprovante",
+		MsgDeletionCancelled: "Pedido de exclusão cancelado",
+		MsgExportStarted:     "Exportação iniciada. Consulte o status para obter o link de download",
+		MsgAPIKeyCreated:     "Guarde esta chave: ela não será exibida novamente",
+		MsgAPIKeyRevoked:     "Chave de API revogada",
+		MsgPetitionDeleted:   "Petição excluída",
+
+		FieldRequired:  "Campo obrigatório",
+		FieldEmail:     "Email inválido",
+		FieldMinString: "Deve ter pelo menos %s caracteres",
+		FieldMinItems:  "Informe pelo menos %s itens",
+		FieldMinNumber: "Deve ser no mínimo %s",
+		FieldMaxString: "Deve ter no máximo %s caracteres",
+		FieldMaxItems:  "Informe no máximo %s itens",
+		FieldMaxNumber: "Deve ser no máximo %s",
+		FieldType:      "Tipo de valor inválido",
+		FieldInvalid:   "Valor inválido",
+
+		DeviceUnknown:     "Dispositivo desconhecido",
+		DeviceBrowserOnOS: "%s em %s",
+	},
+	En: {
+		models.ErrCodeInternal:         "Internal server error",
+		models.ErrCodeInvalidJSON:      "Request body is missing or is not valid JSON",
+		models.ErrCodeValidationFailed: "Invalid data",
+		models.ErrCodeInvalidQuery:     "Invalid query parameters",
+		models.ErrCodeNotImplemented:   "Endpoint not implemented yet",
+		models.ErrCodeTooManyAttempts:  "Too many attempts. Try again in %d seconds",
+		models.ErrCodeRateLimited:      "Rate limit exceeded. Try again in %d seconds",
+		models.ErrCodeRequestTooLarge:  "Request body is too large",
+
+		models.ErrCodeInvalidIdempotencyKey: "Idempotency-Key must be at most %d characters long",
+		models.ErrCodeIdempotencyKeyReused:  "This Idempotency-Key was already used with a different body",
+		models.ErrCodeIdempotencyInProgress: "A request with this Idempotency-Key is still in progress",
+
+		models.ErrCodeMissingToken:        "Authorization token required",
+		models.ErrCodeInvalidTokenFormat:  "Invalid token format",
+		models.ErrCodeInvalidToken:        "Invalid token",
+		models.ErrCodeTokenRevoked:        "Token revoked",
+		models.ErrCodeInvalidAPIKey:       "Invalid API key",
+		models.ErrCodeUnauthenticated:     "User not authenticated",
+		models.ErrCodeInvalidCredentials:  "Incorrect email or password",
+		models.ErrCodeInvalidRefreshToken: "Invalid refresh token",
+		models.ErrCodeSessionExpired:      "Session expired, please log in again",
+		models.ErrCodeSessionRevoked:      "Session revoked, please log in again",
+		models.ErrCodeSessionNotFound:     "Session not found",
+		models.ErrCodeSessionsFetchFailed: "Error fetching sessions",
+		models.ErrCodeSessionEndFailed:    "Error ending session",
+		models.ErrCodeSessionsEndFailed:   "Error ending sessions",
+
+		models.ErrCodeAdminRequired:     "Access restricted to administrators",
+		models.ErrCodeTeamAccessDenied:  "You are not allowed to access this team",
+		models.ErrCodeAPIKeyScopeDenied: "API key not allowed to perform this operation",
+
+		models.ErrCodeTermsNotAccepted:       "You must accept the terms of use",
+		models.ErrCodeEmailAlreadyRegistered: "An account with this email already exists",
+		models.ErrCodeWeakPassword:           "Password is too weak",
+		models.ErrCodeRegistrationFailed:     "Error creating account",
+		models.ErrCodeInviteNotFound:         "Invite not found",
+		models.ErrCodeInviteNotPending:       "This invite has already been used or cancelled",
+		models.ErrCodeInviteExpired:          "This invite has expired",
+		models.ErrCodeInviteEmailMismatch:    "This invite was sent to another email",
+		models.ErrCodeInviteAcceptFailed:     "Error accepting invite",
+
+		models.ErrCodeIncorrectPassword:        "Incorrect password",
+		models.ErrCodeIncorrectCurrentPassword: "Incorrect current password",
+		models.ErrCodeInvalidRecoveryLink:      "Invalid or expired recovery link",
+		models.ErrCodePasswordResetFailed:      "Error resetting password",
+		models.ErrCodePasswordChangeFailed:     "Error changing password",
+		models.ErrCodeSessionsNotEnded:         "Password changed, but ending the sessions failed",
+
+		models.ErrCodeMFAAlreadyEnabled:     "Two-factor authentication is already enabled",
+		models.ErrCodeMFANotEnabled:         "Two-factor authentication is not enabled",
+		models.ErrCodeMFAUnavailable:        "Two-factor authentication unavailable",
+		models.ErrCodeMFAEnrollmentNotFound: "No pending two-factor authentication enrollment",
+		models.ErrCodeInvalidMFACode:        "Invalid code",
+		models.ErrCodeInvalidMFAToken:       "Invalid or expired verification token",
+		models.ErrCodeInvalidRecoveryCode:   "Invalid recovery code",
+
+		models.ErrCodeProfileNotFound:          "Profile not found",
+		models.ErrCodeProfileFetchFailed:       "Error fetching user profile",
+		models.ErrCodeDeletionAlreadyRequested: "An account deletion request is already pending",
+		models.ErrCodeTeamOwnershipPending:     "Transfer the ownership of your teams before deleting your account",
+		models.ErrCodeDeletionRequestFailed:    "Error recording the deletion request",
+		models.ErrCodeDeletionCancelFailed:     "Error cancelling the deletion request",
+		models.ErrCodeDeletionInProgress:       "The account is already being erased and the request can no longer be cancelled",
+		models.ErrCodeDeletionNotFound:         "Deletion request not found",
+		models.ErrCodeNoPendingDeletion:        "No pending deletion request",
+		models.ErrCodeExportInProgress:         "An export is already in progress",
+		models.ErrCodeExportStartFailed:        "Error starting export",
+		models.ErrCodeExportNotFound:           "Export not found",
+		models.ErrCodeExportUnavailable:        "Data export is currently unavailable",
+		models.ErrCodeInvalidDownloadLink:      "Invalid or expired download link",
+
+		models.ErrCodePetitionNotFound:        "Petition not found",
+		models.ErrCodePetitionAccessDenied:    "You are not allowed to access this petition",
+		models.ErrCodePetitionDeleteForbidden: "Only the author of the petition or an administrator can delete it",
+		models.ErrCodePetitionsFetchFailed:    "Error fetching petitions",
+		models.ErrCodePetitionCreateFailed:    "Error creating petition",
+		models.ErrCodePetitionUpdateFailed:    "Error updating petition",
+		models.ErrCodePetitionDeleteFailed:    "Error deleting petition",
+		models.ErrCodeInsufficientTokens:      "Insufficient token balance: creating a petition costs %d tokens",
+
+		models.ErrCodeTeamIDRequired:          "Team ID is required",
+		models.ErrCodeTeamOwnerFetchFailed:    "Error fetching the team owner",
+		models.ErrCodeTeamOwnerNotFound:       "Team owner not found",
+		models.ErrCodeTokenBalanceFetchFailed: "Error fetching token balance",
+
+		models.ErrCodeAPIKeysFetchFailed: "Error fetching API keys",
+		models.ErrCodeInvalidScopes:      "Invalid scopes. Accepted values: %s",
+		models.ErrCodeTeamKeyForbidden:   "You are not allowed to create keys for this team",
+		models.ErrCodeAPIKeyCreateFailed: "Error creating API key",
+		models.ErrCodeAPIKeyUpdateFailed: "Error updating API key",
+		models.ErrCodeAPIKeyRevokeFailed: "Error revoking API key",
+		models.ErrCodeAPIKeyNotFound:     "API key not found",
+
+		models.ErrCodeStatsFetchFailed: "Error fetching statistics",
+
+		MsgLoggedOut:         "Logged out successfully",
+		MsgAllSessionsEnded:  "All sessions have been ended",
+		MsgSessionEnded:      "Session ended",
+		MsgResetEmailSent:    "If the email exists, you will receive instructions to reset your password",
+		MsgPasswordReset:     "Password reset successfully. Please log in again",
+		MsgPasswordChanged:   "Password changed successfully",
+		MsgMFAEnabled:        "Two-factor authentication enabled",
+		MsgMFADisabled:       "Two-factor authentication disabled",
+		MsgDeletionScheduled: "Deletion scheduled. You can cancel it until the scheduled date; keep the receiptToken to get the receipt",
+		MsgDeletionCancelled: "Deletion request cancelled",
+		MsgExportStarted:     "Export started. Check its status to get the download link",
+		MsgAPIKeyCreated:     "Store this key: it will not be shown again",
+		MsgAPIKeyRevoked:     "API key revoked",
+		MsgPetitionDeleted:   "Petition deleted",
+
+		FieldRequired:  "This field is required",
+		FieldEmail:     "Invalid email address",
+		FieldMinString: "Must be at least %s characters long",
+		FieldMinItems:  "Must contain at least %s items",
+		FieldMinNumber: "Must be at least %s",
+		FieldMaxString: "Must be at most %s characters long",
+		FieldMaxItems:  "Must contain at most %s items",
+		FieldMaxNumber: "Must be at most %s",
+		FieldType:      "Invalid value type",
+		FieldInvalid:   "Invalid value",
+
+		DeviceUnknown:     "Unknown device",
+		DeviceBrowserOnOS: "%s on %s",
+	},
+}
@@ -0,0 +1,77 @@
+// Package i18n holds the messages shown to users in pt-BR and English and
+// picks the language from the Accept-Language header. Portuguese is the
+// default: it is what the frontend has always received.
+package i18n
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Languages of the catalog.
+const (
+	PtBR = "pt-BR"
+	En   = "en"
+)
+
+// Default is used when the client accepts none of the catalog languages.
+const Default = PtBR
+
+var (
+	supported = []string{PtBR, En}
+	matcher   = language.NewMatcher([]language.Tag{language.BrazilianPortuguese, language.English})
+)
+
+// Negotiate picks the catalog language that best matches an Accept-Language
+// header, honouring q-values. Any Portuguese variant gets pt-BR.
+func Negotiate(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return Default
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+type ctxKey struct{}
+
+// WithLanguage returns a context whose messages are rendered in lang.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+// Language returns the language stored by WithLanguage, or Default.
+func Language(ctx context.Context) string {
+	if lang, ok := ctx.Value(ctxKey{}).(string); ok {
+		return lang
+	}
+	return Default
+}
+
+// T renders key in lang, formatting args into it. A key missing from lang
+// falls back to the default language, then to the key itself.
+func T(lang, key string, args ...interface{}) string {
+	message, ok := catalog[lang][key]
+	if !ok {
+		if message, ok = catalog[Default][key]; !ok {
+			message = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// Text renders key in the language of ctx.
+func Text(ctx context.Context, key string, args ...interface{}) string {
+	return T(Language(ctx), key, args...)
+}
@@ -0,0 +1,50 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", PtBR},
+		{"pt-BR,pt;q=0.9", PtBR},
+		{"pt-PT", PtBR},
+		{"en-US,en;q=0.9", En},
+		{"fr-FR, en;q=0.5", En},
+		{"pt;q=0.4, en;q=0.8", En},
+		{"de", PtBR},
+		{"not a header;;", PtBR},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+// Every message must exist in every language, or English clients silently
+// get Portuguese.
+func TestCatalogComplete(t *testing.T) {
+	for _, lang := range supported {
+		for _, other := range supported {
+			for key := range catalog[other] {
+				if _, ok := catalog[lang][key]; !ok {
+					t.Errorf("%s: missing %q", lang, key)
+				}
+			}
+		}
+	}
+}
+
+func TestTFallback(t *testing.T) {
+	if got := T(En, "unknown_key"); got != "unknown_key" {
+		t.Errorf("T(unknown) = %q", got)
+	}
+	if got := T("es", FieldRequired); got != catalog[Default][FieldRequired] {
+		t.Errorf("T(es) = %q, want the default language", got)
+	}
+	if got := T(En, FieldMinString, "6"); got != "Must be at least 6 characters long" {
+		t.Errorf("T(args) = %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+// Package logging builds the structured (log/slog) logger used across the
+// backend. Every record carries the request, user and trace IDs found in its
+// context and goes through Redact, so emails, CPFs, passwords and tokens
+// never reach the output.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Formats accepted in LOG_FORMAT.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// New returns a logger writing records of at least level to w.
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	var inner slog.Handler
+	if format == FormatText {
+		inner = slog.NewTextHandler(w, opts)
+	} else {
+		inner = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(&handler{inner: inner})
+}
+
+// ParseLevel reads "debug", "info", "warn" or "error".
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("nível de log inválido: %q", s)
+	}
+	return level, nil
+}
+
+// ValidFormat reports whether format is a known LOG_FORMAT.
+func ValidFormat(format string) bool {
+	return format == FormatJSON || format == FormatText
+}
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	userIDKey
+)
+
+// WithRequestID returns a context whose log records carry request_id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the request ID stored by WithRequestID, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithUserID returns a context whose log records carry user_id.
+func WithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDKey, id)
+}
+
+// handler redacts every record and adds the IDs held by its context.
+type handler struct {
+	inner slog.Handler
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, Redact(r.Message), r.PC)
+	if ctx != nil {
+		if id := RequestID(ctx); id != "" {
+			out.AddAttrs(slog.String("request_id", id))
+		}
+		if id, _ := ctx.Value(userIDKey).(string); id != "" {
+			out.AddAttrs(slog.String("user_id", id))
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			out.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, out)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactAttr(a)
+	}
+	return &handler{inner: h.inner.WithAttrs(redacted)}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{inner: h.inner.WithGroup(name)}
+}
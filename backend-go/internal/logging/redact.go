@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute names whose value is never logged, whatever it
+// looks like.
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"senha":         true,
+	"secret":        true,
+	"token":         true,
+	"authorization": true,
+	"cookie":        true,
+	"api_key":       true,
+	"apikey":        true,
+	"cpf":           true,
+	"email":         true,
+}
+
+var patterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// JSON bodies echoed back by GoTrue or PostgREST
+	{regexp.MustCompile(`(?i)"([a-z_]*(?:password|senha|secret|token|cpf|email)[a-z_]*)"\s*:\s*"[^"]*"`), `"$1":"` + redacted + `"`},
+	// Query strings, e.g. signed download links
+	{regexp.MustCompile(`(?i)\b((?:access_|refresh_)?token|password|code)=[^&\s"]+`), `$1=` + redacted},
+	{regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+`), "Bearer " + redacted},
+	// JWTs and API keys (arg_<prefix>_<secret>)
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), "[REDACTED_JWT]"},
+	{regexp.MustCompile(`\barg_[A-Za-z0-9]+_[A-Za-z0-9_-]+`), "[REDACTED_API_KEY]"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[REDACTED_EMAIL]"},
+	{regexp.MustCompile(`\b\d{3}\.\d{3}\.\d{3}-\d{2}\b|\b\d{11}\b`), "[REDACTED_CPF]"},
+}
+
+// Redact strips emails, CPFs, passwords and tokens from s.
+func Redact(s string) string {
+	for _, p := range patterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	return sensitiveKeys[key] ||
+		strings.HasSuffix(key, "_token") ||
+		strings.HasSuffix(key, "password") ||
+		strings.HasSuffix(key, "_secret")
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	if isSensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(v.String()))
+	case slog.KindGroup:
+		attrs := v.Group()
+		out := make([]any, len(attrs))
+		for i, ga := range attrs {
+			out[i] = redactAttr(ga)
+		}
+		return slog.Group(a.Key, out...)
+	case slog.KindAny:
+		// Errors and arbitrary values are logged as their redacted text
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+		return slog.String(a.Key, Redact(fmt.Sprint(v.Any())))
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
@@ -0,0 +1,164 @@
+// Package metrics holds the Prometheus collectors exposed at /metrics: HTTP
+// traffic by route template, upstream calls (Supabase, R2), logins and token
+// debits.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "argumentum"
+
+// Login results counted by Login.
+const (
+	LoginSuccess     = "success"
+	LoginFailure     = "failure"
+	LoginMFARequired = "mfa_required"
+	LoginThrottled   = "throttled"
+	// LoginError is an attempt that failed on our side (e.g. GoTrue down).
+	LoginError = "error"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	upstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_total",
+		Help:      "Calls to upstream services by service, method and outcome (HTTP status class or error).",
+	}, []string{"service", "method", "outcome"})
+
+	upstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Latency of each attempt of an upstream call.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method"})
+
+	upstreamRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_retries_total",
+		Help:      "Upstream calls sent again after a transient failure.",
+	}, []string{"service"})
+
+	loginAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_attempts_total",
+		Help:      "Login attempts by result.",
+	}, []string{"result"})
+
+	tokenDebits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_debits_total",
+		Help:      "Token debits by reason.",
+	}, []string{"reason"})
+
+	tokensDebited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tokens_debited_total",
+		Help:      "Tokens debited by reason.",
+	}, []string{"reason"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Requests refused by the rate limiter, by route class.",
+	}, []string{"class"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		upstreamRequests, upstreamDuration, upstreamRetries,
+		loginAttempts,
+		tokenDebits, tokensDebited,
+		rateLimited,
+	)
+	for _, result := range []string{LoginSuccess, LoginFailure, LoginMFARequired, LoginThrottled, LoginError} {
+		loginAttempts.WithLabelValues(result)
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// ObserveRequest records one HTTP request. route is the route template
+// (e.g. /teams/:id), never the raw path, to keep the label set bounded.
+func ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+// ObserveUpstream records one attempt of a call to service. status is 0 when
+// the attempt failed without a response.
+func ObserveUpstream(service, method string, status int, elapsed time.Duration) {
+	outcome := "error"
+	if status > 0 {
+		outcome = strconv.Itoa(status/100) + "xx"
+	}
+	upstreamRequests.WithLabelValues(service, method, outcome).Inc()
+	upstreamDuration.WithLabelValues(service, method).Observe(elapsed.Seconds())
+}
+
+// UpstreamRetry counts a call sent again after a transient failure.
+func UpstreamRetry(service string) {
+	upstreamRetries.WithLabelValues(service).Inc()
+}
+
+// SupabaseService names the Supabase service a gateway path belongs to.
+func SupabaseService(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/auth/"):
+		return "gotrue"
+	case strings.HasPrefix(path, "/rest/"):
+		return "postgrest"
+	case strings.HasPrefix(path, "/storage/"):
+		return "storage"
+	case strings.HasPrefix(path, "/functions/"):
+		return "functions"
+	}
+	return "supabase"
+}
+
+// Login counts a login attempt with one of the Login* results.
+func Login(result string) {
+	loginAttempts.WithLabelValues(result).Inc()
+}
+
+// TokenDebit counts a debit of amount tokens for reason (e.g. "petition").
+func TokenDebit(reason string, amount int) {
+	tokenDebits.WithLabelValues(reason).Inc()
+	tokensDebited.WithLabelValues(reason).Add(float64(amount))
+}
+
+// RateLimited counts a request refused by the rate limiter.
+func RateLimited(class string) {
+	rateLimited.WithLabelValues(class).Inc()
+}
@@ -0,0 +1,346 @@
+// Package openapi builds an OpenAPI 3 document from a list of routes whose
+// request and response bodies are Go types: schemas are derived from the
+// json and binding tags, so the spec follows the models without being
+// written by hand.
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps a lower-case HTTP method to its operation.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	// Scope is the API key scope the route requires (x-required-scope).
+	Scope string `json:"x-required-scope,omitempty"`
+
+	// route is the gin path the operation was added with; catch-all
+	// parameters (*filepath) cannot be recovered from the OpenAPI path.
+	route string
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	Responses       map[string]Response       `json:"responses,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Auth says which credentials a route accepts.
+type Auth int
+
+const (
+	Public Auth = iota
+	// Session needs an access token from an interactive login.
+	Session
+	// SessionOrKey also accepts API keys holding Route.Scope.
+	SessionOrKey
+)
+
+// Security scheme names.
+const (
+	SchemeSession = "session"
+	SchemeAPIKey  = "apiKey"
+)
+
+// Route describes one endpoint. Request and Response are zero values of the
+// body types (e.g. models.LoginRequest{}), or a *Schema for shapes without a
+// Go type; Response is wrapped in the {data, message} envelope unless Raw is
+// set.
+type Route struct {
+	Method      string
+	Path        string // gin syntax: /teams/:id
+	Tag         string
+	Summary     string
+	Description string
+	Auth        Auth
+	Scope       string
+	// Params are the query and header parameters; path parameters come
+	// from Path.
+	Params   []Parameter
+	Request  interface{}
+	Response interface{}
+	// Status is the success status; 200 when zero.
+	Status int
+	// Raw responses are not wrapped in ApiResponse; ContentType defaults to
+	// application/json.
+	Raw         bool
+	ContentType string
+	// Errors are the error statuses besides the ones implied by Auth and
+	// Request (401, 400) and the 500 every route may answer.
+	Errors []int
+}
+
+// Builder accumulates routes into a Document.
+type Builder struct {
+	doc     *Document
+	schemas *schemaRegistry
+}
+
+// NewBuilder starts a document. errorType is the body of every error
+// response (models.ApiResponse).
+func NewBuilder(info Info, errorType interface{}) *Builder {
+	b := &Builder{
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   make(map[string]PathItem),
+			Components: Components{
+				Schemas: make(map[string]*Schema),
+				SecuritySchemes: map[string]SecurityScheme{
+					SchemeSession: {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "Access token returned by /auth/login"},
+					SchemeAPIKey:  {Type: "http", Scheme: "bearer", BearerFormat: "arg_<prefix>_<secret>", Description: "API key; only accepted on routes with x-required-scope"},
+				},
+			},
+		},
+	}
+	b.schemas = newSchemaRegistry(b.doc.Components.Schemas)
+
+	errorSchema := b.schemas.schemaFor(errorType)
+	b.doc.Components.Responses = make(map[string]Response)
+	for _, status := range []int{
+		http.StatusBadRequest, http.StatusUnauthorized, http.StatusPaymentRequired, http.StatusForbidden, http.StatusNotFound,
+		http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusNotImplemented,
+	} {
+		b.doc.Components.Responses[responseName(status)] = Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{"application/json": {Schema: errorSchema}},
+		}
+	}
+	return b
+}
+
+// Tag declares a tag with its description, in display order.
+func (b *Builder) Tag(name, description string) {
+	b.doc.Tags = append(b.doc.Tags, Tag{Name: name, Description: description})
+}
+
+// Add documents a route.
+func (b *Builder) Add(r Route) {
+	path, params := convertPath(r.Path)
+	op := &Operation{
+		Summary:     r.Summary,
+		Description: r.Description,
+		OperationID: operationID(r.Method, path),
+		Parameters:  append(params, r.Params...),
+		Responses:   make(map[string]Response),
+		Scope:       r.Scope,
+		route:       r.Path,
+	}
+	if r.Tag != "" {
+		op.Tags = []string{r.Tag}
+	}
+
+	switch r.Auth {
+	case Session:
+		op.Security = []map[string][]string{{SchemeSession: {}}}
+	case SessionOrKey:
+		op.Security = []map[string][]string{{SchemeSession: {}}, {SchemeAPIKey: {}}}
+	}
+
+	if r.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: b.schemas.schemaFor(r.Request)}},
+		}
+	}
+
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = b.successResponse(r, status)
+
+	errs := append([]int{http.StatusInternalServerError}, r.Errors...)
+	if r.Request != nil {
+		errs = append(errs, http.StatusBadRequest)
+	}
+	if r.Auth != Public {
+		errs = append(errs, http.StatusUnauthorized)
+	}
+	if r.Scope != "" {
+		errs = append(errs, http.StatusForbidden)
+	}
+	for _, s := range errs {
+		op.Responses[strconv.Itoa(s)] = Response{Ref: "#/components/responses/" + responseName(s)}
+	}
+
+	item := b.doc.Paths[path]
+	if item == nil {
+		item = make(PathItem)
+		b.doc.Paths[path] = item
+	}
+	item[strings.ToLower(r.Method)] = op
+}
+
+// Document returns the finished document.
+func (b *Builder) Document() *Document {
+	return b.doc
+}
+
+func (b *Builder) successResponse(r Route, status int) Response {
+	resp := Response{Description: http.StatusText(status)}
+	if status >= 300 && status < 400 {
+		// Redirecionamentos não têm corpo
+		return resp
+	}
+	contentType := r.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	var schema *Schema
+	switch {
+	case r.Raw && r.Response == nil:
+		schema = &Schema{Type: "string", Format: "binary"}
+	case r.Raw:
+		schema = b.schemas.schemaFor(r.Response)
+	default:
+		// Same envelope as models.ApiResponse on success
+		schema = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"message": {Type: "string"},
+			},
+		}
+		if r.Response != nil {
+			schema.Properties["data"] = b.schemas.schemaFor(r.Response)
+		}
+	}
+	resp.Content = map[string]MediaType{contentType: {Schema: schema}}
+	return resp
+}
+
+// Has reports whether the document describes method on a gin path.
+func (d *Document) Has(method, ginPath string) bool {
+	path, _ := convertPath(ginPath)
+	item, ok := d.Paths[path]
+	if !ok {
+		return false
+	}
+	_, ok = item[strings.ToLower(method)]
+	return ok
+}
+
+// Operations lists the documented "METHOD /path" pairs in gin syntax.
+func (d *Document) Operations() []string {
+	var ops []string
+	for path, item := range d.Paths {
+		for method, op := range item {
+			route := op.route
+			if route == "" {
+				route = ginPath(path)
+			}
+			ops = append(ops, strings.ToUpper(method)+" "+route)
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// convertPath turns /teams/:id into /teams/{id} plus its path parameters.
+func convertPath(path string) (string, []Parameter) {
+	var params []Parameter
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			name := seg[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func ginPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			segments[i] = ":" + seg[1:len(seg)-1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID derives a stable ID such as getTeamsById.
+func operationID(method, path string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || r == '_'
+	}) {
+		if strings.HasPrefix(part, "{") {
+			sb.WriteString("By")
+			part = strings.Trim(part, "{}")
+		}
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return sb.String()
+}
+
+func responseName(status int) string {
+	return strings.ReplaceAll(http.StatusText(status), " ", "")
+}
@@ -0,0 +1,89 @@
+// Package tracing sets up OpenTelemetry: the global tracer provider, W3C
+// trace context propagation and, when configured, an OTLP/HTTP exporter.
+// Without an exporter the provider stays the OpenTelemetry no-op, so spans
+// cost next to nothing.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters accepted in OTEL_TRACES_EXPORTER.
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+)
+
+// TracerName identifies the spans created by this backend's own code.
+const TracerName = "argumentum-backend"
+
+type Config struct {
+	Exporter    string
+	Endpoint    string
+	ServiceName string
+	// SampleRatio is the fraction of new traces recorded; requests arriving
+	// with a sampled parent are always recorded.
+	SampleRatio float64
+}
+
+// Setup installs the propagator and tracer provider. The returned function
+// flushes pending spans and must be called before the process exits.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Exporter != ExporterOTLP {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer for spans around our own code.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Transport wraps base so every outbound request gets a client span and the
+// traceparent header.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
@@ -2,15 +2,33 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"argumentum-backend/handlers"
+	"argumentum-backend/internal/config"
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/internal/health"
+	"argumentum-backend/internal/logging"
+	"argumentum-backend/internal/tracing"
 	"argumentum-backend/middleware"
+	"argumentum-backend/r2"
+	"argumentum-backend/ratelimit"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
 )
 
 func init() {
@@ -21,75 +39,232 @@ func init() {
 }
 
 func main() {
+	// Configuração única (CONFIG_FILE + variáveis de ambiente), validada antes
+	// de montar qualquer dependência
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Configuração inválida:\n%v", err)
+	}
+
+	// Logs estruturados (JSON) com request_id, user_id e dados pessoais
+	// redigidos; o pacote log padrão também passa por aqui
+	slog.SetDefault(logging.New(os.Stdout, cfg.LogLevel(), cfg.Log.Format))
+
+	if err := utils.ConfigureKeys(cfg.JWT.SigningAlg, cfg.JWT.KeysDir, time.Duration(cfg.JWT.RotationInterval)); err != nil {
+		fatal("Erro ao carregar chaves JWT", err)
+	}
+	utils.SetSecretKey(cfg.MFA.EncryptionKey)
+
+	// Tracing OpenTelemetry (no-op sem OTEL_TRACES_EXPORTER=otlp)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		fatal("Erro ao configurar tracing", err)
+	}
+
 	// Initialize Gin router
-	r := gin.Default()
-
-	// Configure CORS
-	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{"http://localhost:5173", "http://localhost:3000"}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	config.AllowCredentials = true
-	r.Use(cors.New(config))
-
-	// Initialize handlers (que internamente usarão SUPABASE_URL e SUPABASE_SERVICE_ROLE_KEY)
-	authHandler := handlers.NewAuthHandler()
-	petitionHandler := handlers.NewPetitionHandler()
-	profileHandler := handlers.NewProfileHandler()
-	storageHandler := handlers.NewStorageHandler()
-
-	// Auth routes (public)
-	auth := r.Group("/auth")
-	{
-		auth.POST("/login", authHandler.Login)
-		auth.POST("/register", authHandler.Register)
-		auth.POST("/logout", authHandler.Logout)
-		auth.POST("/refresh", authHandler.RefreshToken)
-		auth.POST("/reset-password", authHandler.ResetPassword)
-	}
-
-	// Protected routes
-	protected := r.Group("/")
-	protected.Use(middleware.AuthMiddleware())
-	{
-		protected.GET("/profile", profileHandler.GetProfile)
-		protected.PUT("/profile", profileHandler.UpdateProfile)
-
-		protected.GET("/petitions", petitionHandler.GetPetitions)
-		protected.POST("/petitions", petitionHandler.CreatePetition)
-		protected.GET("/petitions/:id", petitionHandler.GetPetitionByID)
-		protected.PUT("/petitions/:id", petitionHandler.UpdatePetition)
-		protected.DELETE("/petitions/:id", petitionHandler.DeletePetition)
-
-		protected.GET("/teams", petitionHandler.GetTeams)
-		protected.POST("/teams", petitionHandler.CreateTeam)
-		protected.GET("/teams/:id", petitionHandler.GetTeamByID)
-		protected.PUT("/teams/:id", petitionHandler.UpdateTeam)
-		protected.DELETE("/teams/:id", petitionHandler.DeleteTeam)
-		protected.GET("/teams/:id/token-balance", petitionHandler.GetTeamTokenBalance)
-
-		protected.GET("/documents", storageHandler.GetDocuments)
-		protected.POST("/documents/upload", storageHandler.UploadDocument)
-		protected.DELETE("/documents/:id", storageHandler.DeleteDocument)
-
-		protected.GET("/petition-settings", petitionHandler.GetPetitionSettings)
-		protected.PUT("/petition-settings", petitionHandler.UpdatePetitionSettings)
-
-		protected.POST("/storage/signed-url", storageHandler.GetSignedURL)
-		protected.POST("/storage/delete", storageHandler.DeleteFile)
-	}
-
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok", "message": "Argumentum Backend Go is running"})
+	r := gin.New()
+	// X-Forwarded-For só vale vindo dos proxies em TRUSTED_PROXIES; sem eles,
+	// c.ClientIP() é o endereço da conexão e não pode ser forjado
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		fatal("Erro ao configurar TRUSTED_PROXIES", err)
+	}
+	if cfg.IsProduction() && len(cfg.Server.TrustedProxies) == 0 {
+		slog.Warn("TRUSTED_PROXIES não definido: atrás de um balanceador, todos os clientes terão o IP dele")
+	}
+	r.Use(
+		otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithFilter(func(req *http.Request) bool {
+			return req.URL.Path != "/metrics" && !strings.HasPrefix(req.URL.Path, "/health")
+		})),
+		middleware.RequestID(),
+		middleware.Language(),
+		middleware.AccessLog(),
+		middleware.Metrics(),
+		gin.Recovery(),
+	)
+
+	// CORS (CORS_ALLOWED_ORIGINS) e cabeçalhos de segurança; HSTS só em produção
+	var hsts time.Duration
+	if cfg.IsProduction() {
+		hsts = time.Duration(cfg.Security.HSTSMaxAge)
+	}
+	r.Use(
+		middleware.SecurityHeaders(hsts),
+		middleware.CORS(cfg.CORS.AllowedOrigins, time.Duration(cfg.CORS.MaxAge)),
+	)
+
+	// SIGINT/SIGTERM cancelam ctx: o servidor para de aceitar conexões,
+	// termina as requisições em andamento e os jobs em segundo plano
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	var jobs sync.WaitGroup
+	run := func(job func(context.Context)) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			job(jobsCtx)
+		}()
+	}
+
+	// Rotação agendada das chaves de assinatura JWT (JWT_KEY_ROTATION_INTERVAL)
+	run(utils.RunKeyRotation)
+
+	// Cliente único para PostgREST, GoTrue e Storage
+	db := gateway.New(gateway.Config{
+		URL:        cfg.Supabase.URL,
+		ServiceKey: cfg.Supabase.ServiceRoleKey,
+		Timeout:    time.Duration(cfg.Supabase.Timeout),
+		MaxRetries: cfg.Supabase.MaxRetries,
 	})
 
-	// Get port from environment or default to 8080
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Repositórios de dados (Supabase ou memória com seed, conforme DATA_STORE)
+	repos, err := store.NewRepositories(db, cfg.MemoryData(), cfg.Store.SeedFile)
+	if err != nil {
+		fatal("Erro ao carregar seed dos repositórios em memória", err)
 	}
 
-	log.Printf("Server starting on port %s", port)
-	log.Fatal(r.Run(":" + port))
+	// Stores de autenticação (Supabase ou memória, conforme AUTH_STORE)
+	memoryAuth := cfg.MemoryAuth()
+	refreshTokens := store.NewRefreshTokenStore(db, memoryAuth)
+	revocations := store.NewRevocationList(db, memoryAuth)
+
+	// Respostas guardadas para repetições com Idempotency-Key
+	idempotencyKeys := store.NewIdempotencyStore(db, memoryAuth)
+	run(func(ctx context.Context) { store.RunIdempotencyCleanup(ctx, idempotencyKeys, time.Hour) })
+
+	// Limites contra força bruta em login e reset de senha
+	attempts := ratelimit.NewMemoryAttemptStore(time.Hour)
+
+	storage := r2.NewClient(r2.Config{
+		AccountID:       cfg.R2.AccountID,
+		AccessKeyID:     cfg.R2.AccessKeyID,
+		SecretAccessKey: cfg.R2.SecretAccessKey,
+		Bucket:          cfg.R2.Bucket,
+	})
+
+	dataExports := store.NewDataExportStore(db, memoryAuth)
+
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(handlers.AuthDeps{
+		Repositories:  repos,
+		RefreshTokens: refreshTokens,
+		Revocations:   revocations,
+		MFA:           store.NewMFAStore(db, memoryAuth),
+		APIKeys:       store.NewAPIKeyStore(db, memoryAuth),
+		LoginGuard:    ratelimit.NewLoginGuard(attempts),
+		ResetGuard:    ratelimit.NewPasswordResetGuard(attempts),
+
+		AccountDeletions: store.NewAccountDeletionStore(db, memoryAuth),
+		DataExports:      dataExports,
+		DeletionGrace:    cfg.AccountDeletionGrace(),
+		R2:               storage,
+	})
+
+	// Exclusões de conta (LGPD) cujo prazo de cancelamento terminou
+	run(func(ctx context.Context) { authHandler.RunAccountDeletionWorker(ctx, time.Hour) })
+
+	petitionHandler := handlers.NewPetitionHandler(repos)
+	profileHandler := handlers.NewProfileHandler(repos, dataExports, storage)
+	run(func(ctx context.Context) { profileHandler.RunExportCleanup(ctx, time.Hour) })
+
+	// Health checks: /health/live só diz que o processo responde;
+	// /health/ready verifica GoTrue, PostgREST e Storage (resultados em cache)
+	checker := health.NewChecker(time.Duration(cfg.Health.CacheTTL), time.Duration(cfg.Health.CheckTimeout))
+	if cfg.Supabase.URL != "" {
+		checker.Register("gotrue", db.PingAuth)
+		checker.Register("postgrest", db.PingREST)
+		checker.Register("storage", db.PingStorage)
+	}
+	healthHandler := handlers.NewHealthHandler(checker)
+
+	app := &server{
+		auth:      authHandler,
+		petitions: petitionHandler,
+		profile:   profileHandler,
+		storage:   handlers.NewStorageHandler(db),
+		admin:     handlers.NewAdminHandler(repos),
+		health:    healthHandler,
+
+		requireAuth:    middleware.AuthMiddleware(revocations, authHandler),
+		requireSession: middleware.AuthMiddleware(revocations, nil),
+		idempotency:    idempotencyKeys,
+		limiter:        ratelimit.NewLimiter(ratelimit.NewMemoryBucketStore(time.Hour), ratelimit.DefaultQuotas()),
+		metricsToken:   cfg.Metrics.Token,
+
+		longRequestTimeout: time.Duration(cfg.Server.LongRequestTimeout),
+	}
+	app.routes(r)
+
+	srv := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           r,
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeout),
+		ReadTimeout:       time.Duration(cfg.Server.ReadTimeout),
+		WriteTimeout:      time.Duration(cfg.Server.WriteTimeout),
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeout),
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server starting", "port", cfg.Port, "env", cfg.Env)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		stopJobs()
+		fatal("Server stopped", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Com /health/ready em 503, o balanceador tira a instância de rotação
+	// enquanto ela ainda atende; um segundo sinal encerra na hora
+	healthHandler.Drain()
+	if delay := time.Duration(cfg.Server.PreStopDelay); delay > 0 {
+		slog.Info("Draining before shutdown", "delay", delay.String())
+		time.Sleep(delay)
+	}
+
+	slog.Info("Shutting down", "timeout", time.Duration(cfg.Server.ShutdownTimeout).String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout))
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error draining requests", "error", err)
+	}
+
+	stopJobs()
+	jobsDone := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		close(jobsDone)
+	}()
+	select {
+	case <-jobsDone:
+	case <-shutdownCtx.Done():
+		slog.Error("Background jobs did not stop in time")
+	}
+	if err := profileHandler.WaitExports(shutdownCtx); err != nil {
+		slog.Error("Data exports interrupted by shutdown", "error", err)
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Error flushing traces", "error", err)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Server stopped", "error", err)
+	}
+	slog.Info("Server stopped")
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -2,49 +2,105 @@
 package middleware
 
 import (
+	"context"
+
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/logging"
 	"argumentum-backend/models"
+	"argumentum-backend/store"
 	"argumentum-backend/utils"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// APIKeyVerifier resolves an API key to the identity it acts for and the
+// scopes it grants. A nil identity with a nil error means the key is not
+// valid.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*utils.Identity, []string, error)
+}
+
+// AuthMiddleware validates the Bearer JWT and rejects tokens present in the
+// revocation list. When apiKeys is set, API keys are accepted as Bearer
+// credentials too; pass nil for routes only an interactive session may use.
+func AuthMiddleware(revocations store.RevocationList, apiKeys APIKeyVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, models.ApiResponse{
-				Error: "Token de autorização necessário",
-			})
-			c.Abort()
+			apierror.Abort(c, http.StatusUnauthorized, models.ErrCodeMissingToken)
 			return
 		}
 
 		// Extract Bearer token
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, models.ApiResponse{
-				Error: "Formato de token inválido",
-			})
-			c.Abort()
+			apierror.Abort(c, http.StatusUnauthorized, models.ErrCodeInvalidTokenFormat)
 			return
 		}
 
 		token := tokenParts[1]
 
+		if apiKeys != nil && strings.HasPrefix(token, models.APIKeyPrefix) {
+			authenticateAPIKey(c, apiKeys, token)
+			return
+		}
+
 		// Validate JWT token
-		userID, err := utils.ValidateJWT(token)
+		claims, err := utils.ValidateJWT(token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, models.ApiResponse{
-				Error: "Token inválido",
-			})
-			c.Abort()
+			apierror.Abort(c, http.StatusUnauthorized, models.ErrCodeInvalidToken)
 			return
 		}
 
-		// Set user ID in context
-		c.Set("user_id", userID)
+		// Check revocation (logout, logout everywhere, revoked session)
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		revoked, err := revocations.IsRevoked(c.Request.Context(), claims.ID, claims.SessionID, claims.UserID, issuedAt)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "Error checking token revocation", "error", err)
+			apierror.Abort(c, http.StatusInternalServerError, models.ErrCodeInternal)
+			return
+		}
+		if revoked {
+			apierror.Abort(c, http.StatusUnauthorized, models.ErrCodeTokenRevoked)
+			return
+		}
+
+		// Set user ID, roles and claims in context
+		c.Set("user_id", claims.UserID)
+		c.Set("is_admin", claims.IsAdmin)
+		c.Set("team_roles", claims.Teams)
+		c.Set("claims", claims)
+		c.Request = c.Request.WithContext(logging.WithUserID(c.Request.Context(), claims.UserID))
 		c.Next()
 	}
 }
+// authenticateAPIKey sets the same context values as a JWT, minus "claims",
+// plus the key's scopes for RequireScope.
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyVerifier, key string) {
+	identity, scopes, err := apiKeys.VerifyAPIKey(c.Request.Context(), key)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error verifying API key", "error", err)
+		apierror.Abort(c, http.StatusInternalServerError, models.ErrCodeInternal)
+		return
+	}
+	if identity == nil {
+		apierror.Abort(c, http.StatusUnauthorized, models.ErrCodeInvalidAPIKey)
+		return
+	}
+
+	c.Set("user_id", identity.UserID)
+	c.Set("is_admin", identity.IsAdmin)
+	c.Set("team_roles", identity.Teams)
+	c.Set("scopes", scopes)
+	c.Set("api_key_id", identity.APIKeyID)
+	c.Set("api_key_team_id", identity.APIKeyTeamID)
+	c.Request = c.Request.WithContext(logging.WithUserID(c.Request.Context(), identity.UserID))
+	c.Next()
+}
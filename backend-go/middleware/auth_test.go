@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuthMiddlewareRejectsRestrictedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/me", AuthMiddleware(store.NewMemoryRevocationList(), nil), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	mfaPending, err := utils.GenerateMFAPendingJWT("user-1", "")
+	if err != nil {
+		t.Fatalf("GenerateMFAPendingJWT: %v", err)
+	}
+	download, err := utils.GenerateDownloadToken("export:1", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateDownloadToken: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"access token", tokenFor(t, false, nil), http.StatusOK},
+		// Only the password was checked: the 2FA step is still pending
+		{"mfa pending token", mfaPending, http.StatusUnauthorized},
+		{"download token", download, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := doRequest(r, http.MethodGet, "/me", tt.token); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
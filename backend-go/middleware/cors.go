@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"argumentum-backend/models"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORS answers cross-origin requests from the allowed origins (validated by
+// internal/config). A pattern such as https://*.vercel.app matches exactly
+// one subdomain label at the star, so preview deployments work without
+// opening every host under the domain. Requests from other origins get 403.
+func CORS(origins []string, maxAge time.Duration) gin.HandlerFunc {
+	return cors.New(cors.Config{
+		AllowOriginFunc: OriginMatcher(origins),
+		AllowMethods:    []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
+		AllowHeaders:    []string{"Origin", "Content-Type", "Accept", "Accept-Language", "Authorization", RequestIDHeader, models.IdempotencyKeyHeader},
+		// Readable by the frontend besides the CORS-safelisted headers
+		ExposeHeaders: []string{
+			RequestIDHeader, "Content-Language", "Retry-After",
+			RateLimitLimitHeader, RateLimitRemainingHeader, RateLimitResetHeader, RateLimitPolicyHeader,
+			models.IdempotentReplayedHeader,
+		},
+		AllowCredentials: true,
+		MaxAge:           maxAge,
+	})
+}
+
+type originPattern struct {
+	scheme, host, port string
+	wildcard           bool
+}
+
+// OriginMatcher reports whether an Origin header matches one of patterns.
+func OriginMatcher(patterns []string) func(origin string) bool {
+	var parsed []originPattern
+	for _, p := range patterns {
+		u, err := url.Parse(strings.ToLower(p))
+		if err != nil {
+			continue
+		}
+		host := u.Hostname()
+		parsed = append(parsed, originPattern{
+			scheme:   u.Scheme,
+			host:     strings.TrimPrefix(host, "*."),
+			port:     u.Port(),
+			wildcard: strings.HasPrefix(host, "*."),
+		})
+	}
+
+	return func(origin string) bool {
+		u, err := url.Parse(strings.ToLower(origin))
+		if err != nil || u.Path != "" {
+			return false
+		}
+		host := u.Hostname()
+		for _, p := range parsed {
+			if u.Scheme != p.scheme || u.Port() != p.port {
+				continue
+			}
+			if !p.wildcard {
+				if host == p.host {
+					return true
+				}
+				continue
+			}
+			label, ok := strings.CutSuffix(host, "."+p.host)
+			if ok && label != "" && !strings.Contains(label, ".") {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import "testing"
+
+func TestOriginMatcher(t *testing.T) {
+	match := OriginMatcher([]string{
+		"https://app.argumentum.com.br",
+		"https://*.vercel.app",
+		"http://localhost:5173",
+	})
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.argumentum.com.br", true},
+		{"https://APP.argumentum.com.br", true},
+		{"http://app.argumentum.com.br", false},
+		{"https://app.argumentum.com.br:8443", false},
+		{"https://evil-app.argumentum.com.br", false},
+		{"https://argumentum-git-main.vercel.app", true},
+		{"https://vercel.app", false},
+		{"https://.vercel.app", false},
+		{"https://a.b.vercel.app", false},
+		{"https://evilvercel.app", false},
+		{"https://vercel.app.evil.com", false},
+		{"http://argumentum.vercel.app", false},
+		{"http://localhost:5173", true},
+		{"http://localhost:3000", false},
+		{"null", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := match(tt.origin); got != tt.want {
+			t.Errorf("match(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
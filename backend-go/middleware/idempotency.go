@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxIdempotencyKey and maxIdempotentBody bound what a client can make
+	// the server hash and keep.
+	maxIdempotencyKey = 255
+	maxIdempotentBody = 1 << 20
+
+	// idempotencyStaleAfter is how long a first attempt may run without a
+	// response before its reservation counts as abandoned (e.g. the
+	// instance died) and a retry may take it over.
+	idempotencyStaleAfter = 2 * time.Minute
+)
+
+// Idempotency makes a mutating route safe to retry. The first request with
+// an Idempotency-Key runs normally and its response is kept for ttl, per
+// user, method and path; retries get the same response back with
+// Idempotent-Replayed: true instead of running again. Reusing the key with
+// another body answers 422, and a retry that arrives while the first attempt
+// is still running answers 409. 5xx responses are not kept, so those can be
+// retried. Requests without the header are not affected. It must run after
+// AuthMiddleware.
+func Idempotency(idempotency store.IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(models.IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			apierror.Abort(c, http.StatusBadRequest, models.ErrCodeInvalidIdempotencyKey, maxIdempotencyKey)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBody+1))
+		if err != nil {
+			apierror.Abort(c, http.StatusBadRequest, models.ErrCodeInvalidJSON)
+			return
+		}
+		if len(body) > maxIdempotentBody {
+			apierror.Abort(c, http.StatusRequestEntityTooLarge, models.ErrCodeRequestTooLarge)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// The outcome must be saved even if the client hangs up mid-request
+		ctx := context.WithoutCancel(c.Request.Context())
+		userID := c.GetString("user_id")
+		now := time.Now().UTC()
+		req := &models.IdempotentRequest{
+			Key:         utils.HashToken(strings.Join([]string{userID, c.Request.Method, c.Request.URL.Path, key}, "\n")),
+			UserID:      userID,
+			RequestHash: utils.HashToken(string(body)),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		existing, err := idempotency.Reserve(ctx, req)
+		if err == nil && existing != nil && existing.Status == 0 && existing.RequestHash == req.RequestHash &&
+			now.Sub(existing.CreatedAt) > idempotencyStaleAfter {
+			// Só um dos retries assume a reserva abandonada; os demais veem a nova
+			var taken bool
+			if taken, err = idempotency.TakeOver(ctx, req, now.Add(-idempotencyStaleAfter)); err == nil {
+				if taken {
+					existing = nil
+				} else {
+					existing, err = idempotency.Reserve(ctx, req)
+				}
+			}
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Error reserving idempotency key", "error", err)
+			apierror.Abort(c, http.StatusInternalServerError, models.ErrCodeInternal)
+			return
+		}
+		if existing != nil {
+			replay(c, existing, req.RequestHash)
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if status := recorder.Status(); status >= http.StatusInternalServerError {
+			err = idempotency.Release(ctx, req.Key)
+		} else {
+			err = idempotency.Complete(ctx, req.Key, status, recorder.Header().Get("Content-Type"), recorder.body.String())
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Error saving idempotent response", "error", err)
+		}
+	}
+}
+
+func replay(c *gin.Context, first *models.IdempotentRequest, requestHash string) {
+	switch {
+	case first.RequestHash != requestHash:
+		apierror.Abort(c, http.StatusUnprocessableEntity, models.ErrCodeIdempotencyKeyReused)
+	case first.Status == 0:
+		apierror.Abort(c, http.StatusConflict, models.ErrCodeIdempotencyInProgress)
+	default:
+		c.Header(models.IdempotentReplayedHeader, "true")
+		c.Data(first.Status, first.ContentType, []byte(first.Body))
+		c.Abort()
+	}
+}
+
+// bodyRecorder keeps a copy of the response body while writing it.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
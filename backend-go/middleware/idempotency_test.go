@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	r := gin.New()
+	r.POST("/petitions",
+		func(c *gin.Context) { c.Set("user_id", c.GetHeader("X-User")); c.Next() },
+		Idempotency(store.NewMemoryIdempotencyStore(), time.Hour),
+		func(c *gin.Context) {
+			calls++
+			c.JSON(http.StatusCreated, gin.H{"call": calls})
+		},
+	)
+
+	send := func(user, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/petitions", strings.NewReader(body))
+		req.Header.Set("X-User", user)
+		if key != "" {
+			req.Header.Set(models.IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("u1", "k1", `{"title":"a"}`)
+	retry := send("u1", "k1", `{"title":"a"}`)
+	if calls != 1 || retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("retry ran the handler again or changed the response: calls=%d %d %s", calls, retry.Code, retry.Body)
+	}
+	if retry.Header().Get(models.IdempotentReplayedHeader) != "true" {
+		t.Error("replayed response is not marked")
+	}
+
+	if w := send("u1", "k1", `{"title":"b"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("same key with another body: status %d, want 422", w.Code)
+	}
+
+	// Keys are per user, and requests without a key always run
+	send("u2", "k1", `{"title":"a"}`)
+	send("u1", "", `{"title":"a"}`)
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+// Concurrent retries of an abandoned first attempt: exactly one takes the
+// reservation over and runs, the others see it in flight.
+func TestIdempotencyStaleTakeover(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	idempotency := store.NewMemoryIdempotencyStore()
+	body := `{"title":"a"}`
+	now := time.Now().UTC()
+	abandoned := &models.IdempotentRequest{
+		Key:         utils.HashToken(strings.Join([]string{"u1", http.MethodPost, "/petitions", "k1"}, "\n")),
+		UserID:      "u1",
+		RequestHash: utils.HashToken(body),
+		CreatedAt:   now.Add(-2 * idempotencyStaleAfter),
+		ExpiresAt:   now.Add(time.Hour),
+	}
+	if _, err := idempotency.Reserve(context.Background(), abandoned); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	r := gin.New()
+	r.POST("/petitions",
+		func(c *gin.Context) { c.Set("user_id", "u1"); c.Next() },
+		Idempotency(idempotency, time.Hour),
+		func(c *gin.Context) {
+			calls.Add(1)
+			<-release
+			c.JSON(http.StatusCreated, gin.H{})
+		},
+	)
+
+	const retries = 8
+	codes := make(chan int, retries)
+	var wg sync.WaitGroup
+	for i := 0; i < retries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/petitions", strings.NewReader(body))
+			req.Header.Set(models.IdempotencyKeyHeader, "k1")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			codes <- w.Code
+		}()
+	}
+	// The losers answer without waiting for the winner
+	for i := 0; i < retries-1; i++ {
+		if code := <-codes; code != http.StatusConflict {
+			t.Errorf("retry status = %d, want 409", code)
+		}
+	}
+	close(release)
+	wg.Wait()
+	if code := <-codes; code != http.StatusCreated {
+		t.Errorf("winning retry status = %d, want 201", code)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("handler ran %d times, want 1", n)
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"argumentum-backend/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Language picks the response language from Accept-Language and stores it
+// in the request context, where apierror and i18n.Text read it.
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), lang))
+		c.Next()
+	}
+}
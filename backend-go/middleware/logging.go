@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+
+	"argumentum-backend/internal/logging"
+	"argumentum-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID between clients, proxies and logs.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limits what a client may send as its own request ID, so the
+// header cannot be used to inject arbitrary text into the logs.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID reuses the caller's X-Request-ID when it looks sane, or assigns
+// a new one. The ID is echoed in the response and attached to the request
+// context so every log record of the request carries it.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = utils.GenerateID()
+		}
+		c.Set("request_id", id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// AccessLog writes one record per request with its status and latency. The
+// user ID comes from the context once AuthMiddleware ran. The query string is
+// left out: it may carry signed tokens.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		slog.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/internal/metrics"
+	"argumentum-backend/models"
+	"argumentum-backend/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limit response headers (draft-ietf-httpapi-ratelimit-headers).
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+// RateLimit throttles requests of class per API key, team and user, and
+// reports the bucket closest to running out in the RateLimit-* headers. It
+// must run after AuthMiddleware. A limiter error lets the request through:
+// losing the store must not take the API down with it.
+func RateLimit(limiter *ratelimit.Limiter, class ratelimit.Class) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		decision, limited, err := limiter.Allow(c.Request.Context(), class, ratelimit.Subject{
+			UserID:   c.GetString("user_id"),
+			TeamIDs:  requestTeams(c),
+			APIKeyID: c.GetString("api_key_id"),
+		})
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "Error checking rate limit", "error", err)
+			c.Next()
+			return
+		}
+		if !limited {
+			c.Next()
+			return
+		}
+
+		limit := decision.Limit
+		c.Header(RateLimitLimitHeader, strconv.Itoa(limit.Burst))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(decision.Remaining))
+		c.Header(RateLimitResetHeader, strconv.Itoa(ceilSeconds(decision.Reset)))
+		c.Header(RateLimitPolicyHeader, strconv.Itoa(limit.Burst)+";w="+strconv.Itoa(ceilSeconds(limit.Period)))
+
+		if !decision.Allowed {
+			retry := ceilSeconds(decision.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retry))
+			metrics.RateLimited(string(class))
+			apierror.Abort(c, http.StatusTooManyRequests, models.ErrCodeRateLimited, retry)
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestTeams returns the teams whose quota the request counts against: the
+// team of a team-scoped API key, the team named by a /teams/:id route when
+// the caller is a member, or else every team in the caller's claims, so
+// sessions share the team quota like the team's keys do.
+func requestTeams(c *gin.Context) []string {
+	if teamID := c.GetString("api_key_team_id"); teamID != "" {
+		return []string{teamID}
+	}
+
+	teams, _ := c.Get("team_roles")
+	teamRoles, _ := teams.(map[string]string)
+	if strings.HasPrefix(c.FullPath(), "/teams/:id") {
+		if _, isMember := teamRoles[c.Param("id")]; isMember {
+			return []string{c.Param("id")}
+		}
+	}
+
+	ids := make([]string, 0, len(teamRoles))
+	for id := range teamRoles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"argumentum-backend/models"
+	"argumentum-backend/ratelimit"
+	"argumentum-backend/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sessions count against the team quota: the team of a /teams/:id route, or
+// every team of the user elsewhere.
+func TestRateLimitCountsSessionsAgainstTeams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryBucketStore(time.Hour), map[ratelimit.Class]ratelimit.Quota{
+		ratelimit.ClassRead: {Team: ratelimit.Limit{Burst: 2, Period: time.Hour}},
+	})
+	r := gin.New()
+	protected := r.Group("/")
+	protected.Use(AuthMiddleware(store.NewMemoryRevocationList(), nil))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	read := RateLimit(limiter, ratelimit.ClassRead)
+	protected.GET("/petitions", read, ok)
+	protected.GET("/teams/:id", read, ok)
+
+	member := tokenFor(t, false, map[string]string{testTeamID: models.TeamRoleOperador})
+	other := tokenFor(t, false, map[string]string{testTeamID: models.TeamRoleOwner, "team-2": models.TeamRoleOwner})
+	solo := tokenFor(t, false, nil)
+
+	// Two members of the same team use up its quota together
+	if got := doRequest(r, "GET", "/teams/"+testTeamID, member); got != http.StatusOK {
+		t.Fatalf("first request = %d", got)
+	}
+	if got := doRequest(r, "GET", "/petitions", other); got != http.StatusOK {
+		t.Fatalf("second request = %d", got)
+	}
+	if got := doRequest(r, "GET", "/petitions", member); got != http.StatusTooManyRequests {
+		t.Fatalf("third request = %d, want 429 from the team bucket", got)
+	}
+
+	// A route of another team does not touch the used-up quota
+	if got := doRequest(r, "GET", "/teams/team-2", other); got != http.StatusOK {
+		t.Fatalf("team-2 route = %d, want 200", got)
+	}
+	// Without a team only the user quota applies (unlimited here)
+	if got := doRequest(r, "GET", "/petitions", solo); got != http.StatusOK {
+		t.Fatalf("user without team = %d, want 200", got)
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"argumentum-backend/internal/apierror"
+	"argumentum-backend/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin only lets platform administrators through. It must run after
+// AuthMiddleware.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("is_admin") {
+			apierror.Abort(c, http.StatusForbidden, models.ErrCodeAdminRequired)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireTeamRole checks that the user belongs to the team named by the
+// route parameter param with one of the given roles. With no roles, any
+// membership is enough. The user's role is stored in the context as
+// "team_role". It must run after AuthMiddleware.
+func RequireTeamRole(param string, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teamID := c.Param(param)
+		if teamID == "" {
+			apierror.Abort(c, http.StatusBadRequest, models.ErrCodeTeamIDRequired)
+			return
+		}
+
+		teams, _ := c.Get("team_roles")
+		teamRoles, _ := teams.(map[string]string)
+		role, isMember := teamRoles[teamID]
+		if !isMember || !hasRole(role, roles) {
+			apierror.Abort(c, http.StatusForbidden, models.ErrCodeTeamAccessDenied)
+			return
+		}
+
+		c.Set("team_role", role)
+		c.Next()
+	}
+}
+
+// RequireScope lets API keys through only when they were granted scope.
+// Interactive sessions are not scoped and always pass. It must run after
+// AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted, isAPIKey := c.Get("scopes")
+		if isAPIKey {
+			scopes, _ := granted.([]string)
+			if !hasScope(scopes, scope) {
+				apierror.Abort(c, http.StatusForbidden, models.ErrCodeAPIKeyScopeDenied)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+func hasRole(role string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, r := range allowed {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
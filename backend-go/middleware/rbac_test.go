@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"argumentum-backend/models"
+	"argumentum-backend/store"
+	"argumentum-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testTeamID = "team-1"
+
+func newRBACRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	protected := r.Group("/")
+	protected.Use(AuthMiddleware(store.NewMemoryRevocationList(), nil))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	protected.GET("/admin", RequireAdmin(), ok)
+	protected.GET("/teams/:id", RequireTeamRole("id"), ok)
+	protected.PUT("/teams/:id", RequireTeamRole("id", models.TeamRoleOwner), ok)
+	protected.POST("/teams/:id/invites", RequireTeamRole("id", models.TeamRoleOwner, models.TeamRoleGestor), ok)
+	return r
+}
+
+func tokenFor(t *testing.T, isAdmin bool, teams map[string]string) string {
+	t.Helper()
+	token, err := utils.GenerateJWT(utils.Identity{
+		UserID:    "user-1",
+		SessionID: "session-1",
+		IsAdmin:   isAdmin,
+		Teams:     teams,
+	})
+	if err != nil {
+		t.Fatalf("GenerateJWT: %v", err)
+	}
+	return token
+}
+
+func doRequest(r *gin.Engine, method, path, token string) int {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRequireAdmin(t *testing.T) {
+	r := newRBACRouter()
+
+	tests := []struct {
+		name    string
+		isAdmin bool
+		want    int
+	}{
+		{"admin", true, http.StatusOK},
+		{"regular user", false, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := doRequest(r, "GET", "/admin", tokenFor(t, tt.isAdmin, nil)); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireTeamRole(t *testing.T) {
+	r := newRBACRouter()
+
+	tests := []struct {
+		name   string
+		role   string
+		method string
+		path   string
+		want   int
+	}{
+		{"owner reads team", models.TeamRoleOwner, "GET", "/teams/" + testTeamID, http.StatusOK},
+		{"gestor reads team", models.TeamRoleGestor, "GET", "/teams/" + testTeamID, http.StatusOK},
+		{"operador reads team", models.TeamRoleOperador, "GET", "/teams/" + testTeamID, http.StatusOK},
+		{"non-member reads team", "", "GET", "/teams/" + testTeamID, http.StatusForbidden},
+		{"member of another team", models.TeamRoleOwner, "GET", "/teams/team-2", http.StatusForbidden},
+
+		{"owner updates team", models.TeamRoleOwner, "PUT", "/teams/" + testTeamID, http.StatusOK},
+		{"gestor updates team", models.TeamRoleGestor, "PUT", "/teams/" + testTeamID, http.StatusForbidden},
+		{"operador updates team", models.TeamRoleOperador, "PUT", "/teams/" + testTeamID, http.StatusForbidden},
+
+		{"owner invites", models.TeamRoleOwner, "POST", "/teams/" + testTeamID + "/invites", http.StatusOK},
+		{"gestor invites", models.TeamRoleGestor, "POST", "/teams/" + testTeamID + "/invites", http.StatusOK},
+		{"operador invites", models.TeamRoleOperador, "POST", "/teams/" + testTeamID + "/invites", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams := map[string]string{}
+			if tt.role != "" {
+				teams[testTeamID] = tt.role
+			}
+			if got := doRequest(r, tt.method, tt.path, tokenFor(t, false, teams)); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireTeamRoleAdminIsNotImplicitMember(t *testing.T) {
+	r := newRBACRouter()
+	if got := doRequest(r, "GET", "/teams/"+testTeamID, tokenFor(t, true, nil)); got != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", got, http.StatusForbidden)
+	}
+}
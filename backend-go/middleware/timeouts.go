@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LongRequest moves the connection's read and write deadlines timeout ahead,
+// overriding the server-wide ReadTimeout and WriteTimeout, for routes that
+// stream whole files. Zero leaves the server deadlines alone.
+func LongRequest(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout > 0 {
+			deadline := time.Now().Add(timeout)
+			rc := http.NewResponseController(c.Writer)
+			if err := rc.SetReadDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				slog.WarnContext(c.Request.Context(), "Error extending read deadline", "error", err)
+			}
+			if err := rc.SetWriteDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				slog.WarnContext(c.Request.Context(), "Error extending write deadline", "error", err)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLongRequestOutlivesWriteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	slow := func(c *gin.Context) {
+		time.Sleep(300 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	}
+	r := gin.New()
+	r.GET("/short", slow)
+	r.GET("/long", LongRequest(time.Minute), slow)
+
+	srv := httptest.NewUnstartedServer(r)
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	get := func(path string) (string, error) {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+	if body, err := get("/short"); err == nil && body == "done" {
+		t.Fatal("response past WriteTimeout reached the client; the test proves nothing")
+	}
+	if body, err := get("/long"); err != nil || body != "done" {
+		t.Fatalf("long request = %q, %v; want done", body, err)
+	}
+}
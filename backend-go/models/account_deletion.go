@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Account deletion request statuses.
+const (
+	AccountDeletionPending   = "pending"
+	AccountDeletionCancelled = "cancelled"
+	AccountDeletionCompleted = "completed"
+)
+
+// AccountDeletion is an LGPD erasure request. The account is only erased
+// after ScheduledFor, so the user can still cancel during the grace period.
+// The row outlives the user as proof of erasure; it only keeps the user id,
+// a hash of the email and the signed receipt.
+type AccountDeletion struct {
+	ID               string     `json:"id"`
+	UserID           string     `json:"user_id"`
+	EmailSHA256      string     `json:"email_sha256"`
+	Reason           string     `json:"reason,omitempty"`
+	Status           string     `json:"status"`
+	ReceiptTokenHash string     `json:"receipt_token_hash"`
+	RequestedAt      time.Time  `json:"requested_at"`
+	ScheduledFor     time.Time  `json:"scheduled_for"`
+	CancelledAt      *time.Time `json:"cancelled_at,omitempty"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	Receipt          string     `json:"receipt,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+	// LockedUntil is the lease of the worker erasing the account.
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+	Reason   string `json:"reason" binding:"max=500"`
+}
+
+// AccountDeletionResponse describes a request to its owner. ReceiptToken is
+// only returned when the request is created; it is needed to fetch the
+// receipt once the account no longer exists.
+type AccountDeletionResponse struct {
+	ID           string     `json:"id"`
+	Status       string     `json:"status"`
+	RequestedAt  time.Time  `json:"requestedAt"`
+	ScheduledFor time.Time  `json:"scheduledFor"`
+	CompletedAt  *time.Time `json:"completedAt,omitempty"`
+	ReceiptToken string     `json:"receiptToken,omitempty"`
+	Receipt      string     `json:"receipt,omitempty"`
+}
+
+// TeamOwnershipConflict lists a team that still has other members and must
+// be handed over before its owner can delete the account.
+type TeamOwnershipConflict struct {
+	TeamID  string `json:"teamId"`
+	Members int    `json:"members"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// AdminStats is the row returned by public.get_admin_stats. TotalUsers leaves
+// platform administrators out.
+type AdminStats struct {
+	TotalPetitions       int            `json:"total_petitions"`
+	TotalUsers           int            `json:"total_users"`
+	PendingPetitions     int            `json:"pending_petitions"`
+	DistributionByStatus map[string]int `json:"distribution_by_status"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// APIKeyPrefix starts every API key, telling it apart from a JWT in the
+// Authorization header.
+const APIKeyPrefix = "arg_"
+
+// API key scopes. Interactive sessions are not scoped; an API key only
+// reaches the routes guarded by one of its scopes.
+const (
+	ScopeProfileRead    = "profile:read"
+	ScopePetitionsRead  = "petitions:read"
+	ScopePetitionsWrite = "petitions:write"
+	ScopeTeamsRead      = "teams:read"
+	ScopeDocumentsRead  = "documents:read"
+	ScopeDocumentsWrite = "documents:write"
+)
+
+// APIKeyScopes lists every scope a key may be granted.
+var APIKeyScopes = []string{
+	ScopeProfileRead,
+	ScopePetitionsRead,
+	ScopePetitionsWrite,
+	ScopeTeamsRead,
+	ScopeDocumentsRead,
+	ScopeDocumentsWrite,
+}
+
+// APIKey is a long-lived credential for integrations. Only the SHA-256 hash
+// of the key is stored; Prefix is the public part shown in listings so users
+// can tell their keys apart. A key with TeamID set only acts on that team.
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	TeamID     string     `json:"team_id,omitempty"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"key_hash"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	TeamID        string   `json:"teamId"`
+	ExpiresInDays int      `json:"expiresInDays" binding:"omitempty,min=1,max=365"`
+}
+
+type UpdateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"omitempty,max=100"`
+	Scopes []string `json:"scopes"`
+}
+
+// APIKeyInfo is what the API returns about a key; the secret itself only
+// appears once, in CreateAPIKeyResponse.
+type APIKeyInfo struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	TeamID     string     `json:"teamId,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKeyInfo
+	Key string `json:"key"`
+}
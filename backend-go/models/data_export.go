@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Data export statuses.
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportReady      = "ready"
+	DataExportFailed     = "failed"
+)
+
+// DataExport is an LGPD portability export: a ZIP with everything held about
+// the user, built in the background and kept in R2 under ObjectKey until
+// ExpiresAt.
+type DataExport struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	Status      string     `json:"status"`
+	ObjectKey   string     `json:"object_key,omitempty"`
+	SizeBytes   int64      `json:"size_bytes"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+}
+
+// DataExportResponse describes an export to its owner. DownloadURL is set
+// once the export is ready and stays valid for a short time only.
+type DataExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	SizeBytes   int64      `json:"sizeBytes,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	DownloadURL string     `json:"downloadUrl,omitempty"`
+}
@@ -0,0 +1,133 @@
+package models
+
+// FieldError describes one invalid field of a request body. Code is one of
+// the FieldCode* values; Param carries the limit of min/max rules.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Field error codes.
+const (
+	FieldCodeRequired = "required"
+	FieldCodeEmail    = "email"
+	FieldCodeMin      = "min"
+	FieldCodeMax      = "max"
+	FieldCodeType     = "type"
+	FieldCodeInvalid  = "invalid"
+)
+
+// Error codes returned in ApiResponse.Code. They are part of the API
+// contract: clients branch on them, so an existing code never changes
+// meaning. The message shown next to it comes from internal/i18n.
+const (
+	// Generic
+	ErrCodeInternal         = "internal_error"
+	ErrCodeInvalidJSON      = "invalid_json"
+	ErrCodeValidationFailed = "validation_failed"
+	ErrCodeInvalidQuery     = "invalid_query"
+	ErrCodeNotImplemented   = "not_implemented"
+	ErrCodeTooManyAttempts  = "too_many_attempts"
+	ErrCodeRateLimited      = "rate_limited"
+	ErrCodeRequestTooLarge  = "request_too_large"
+
+	// Idempotency keys
+	ErrCodeInvalidIdempotencyKey = "invalid_idempotency_key"
+	ErrCodeIdempotencyKeyReused  = "idempotency_key_reused"
+	ErrCodeIdempotencyInProgress = "idempotency_request_in_progress"
+
+	// Authentication
+	ErrCodeMissingToken        = "missing_token"
+	ErrCodeInvalidTokenFormat  = "invalid_token_format"
+	ErrCodeInvalidToken        = "invalid_token"
+	ErrCodeTokenRevoked        = "token_revoked"
+	ErrCodeInvalidAPIKey       = "invalid_api_key"
+	ErrCodeUnauthenticated     = "unauthenticated"
+	ErrCodeInvalidCredentials  = "invalid_credentials"
+	ErrCodeInvalidRefreshToken = "invalid_refresh_token"
+	ErrCodeSessionExpired      = "session_expired"
+	ErrCodeSessionRevoked      = "session_revoked"
+	ErrCodeSessionNotFound     = "session_not_found"
+	ErrCodeSessionsFetchFailed = "sessions_fetch_failed"
+	ErrCodeSessionEndFailed    = "session_end_failed"
+	ErrCodeSessionsEndFailed   = "sessions_end_failed"
+
+	// Authorization
+	ErrCodeAdminRequired     = "admin_required"
+	ErrCodeTeamAccessDenied  = "team_access_denied"
+	ErrCodeAPIKeyScopeDenied = "api_key_scope_denied"
+
+	// Registration and invites
+	ErrCodeTermsNotAccepted       = "terms_not_accepted"
+	ErrCodeEmailAlreadyRegistered = "email_already_registered"
+	ErrCodeWeakPassword           = "weak_password"
+	ErrCodeRegistrationFailed     = "registration_failed"
+	ErrCodeInviteNotFound         = "invite_not_found"
+	ErrCodeInviteNotPending       = "invite_not_pending"
+	ErrCodeInviteExpired          = "invite_expired"
+	ErrCodeInviteEmailMismatch    = "invite_email_mismatch"
+	ErrCodeInviteAcceptFailed     = "invite_accept_failed"
+
+	// Passwords
+	ErrCodeIncorrectPassword        = "incorrect_password"
+	ErrCodeIncorrectCurrentPassword = "incorrect_current_password"
+	ErrCodeInvalidRecoveryLink      = "invalid_recovery_link"
+	ErrCodePasswordResetFailed      = "password_reset_failed"
+	ErrCodePasswordChangeFailed     = "password_change_failed"
+	ErrCodeSessionsNotEnded         = "password_changed_sessions_not_ended"
+
+	// Two-factor authentication
+	ErrCodeMFAAlreadyEnabled     = "mfa_already_enabled"
+	ErrCodeMFANotEnabled         = "mfa_not_enabled"
+	ErrCodeMFAUnavailable        = "mfa_unavailable"
+	ErrCodeMFAEnrollmentNotFound = "mfa_enrollment_not_found"
+	ErrCodeInvalidMFACode        = "invalid_mfa_code"
+	ErrCodeInvalidMFAToken       = "invalid_mfa_token"
+	ErrCodeInvalidRecoveryCode   = "invalid_recovery_code"
+
+	// Profile, account deletion and data export
+	ErrCodeProfileNotFound          = "profile_not_found"
+	ErrCodeProfileFetchFailed       = "profile_fetch_failed"
+	ErrCodeDeletionAlreadyRequested = "deletion_already_requested"
+	ErrCodeTeamOwnershipPending     = "team_ownership_pending"
+	ErrCodeDeletionRequestFailed    = "deletion_request_failed"
+	ErrCodeDeletionCancelFailed     = "deletion_cancel_failed"
+	ErrCodeDeletionInProgress       = "deletion_in_progress"
+	ErrCodeDeletionNotFound         = "deletion_not_found"
+	ErrCodeNoPendingDeletion        = "no_pending_deletion"
+	ErrCodeExportInProgress         = "export_in_progress"
+	ErrCodeExportStartFailed        = "export_start_failed"
+	ErrCodeExportNotFound           = "export_not_found"
+	ErrCodeExportUnavailable        = "export_unavailable"
+	ErrCodeInvalidDownloadLink      = "invalid_download_link"
+
+	// Petitions
+	ErrCodePetitionNotFound        = "petition_not_found"
+	ErrCodePetitionAccessDenied    = "petition_access_denied"
+	ErrCodePetitionDeleteForbidden = "petition_delete_forbidden"
+	ErrCodePetitionsFetchFailed    = "petitions_fetch_failed"
+	ErrCodePetitionCreateFailed    = "petition_create_failed"
+	ErrCodePetitionUpdateFailed    = "petition_update_failed"
+	ErrCodePetitionDeleteFailed    = "petition_delete_failed"
+	ErrCodeInsufficientTokens      = "insufficient_tokens"
+
+	// Teams
+	ErrCodeTeamIDRequired          = "team_id_required"
+	ErrCodeTeamOwnerFetchFailed    = "team_owner_fetch_failed"
+	ErrCodeTeamOwnerNotFound       = "team_owner_not_found"
+	ErrCodeTokenBalanceFetchFailed = "token_balance_fetch_failed"
+
+	// API keys
+	ErrCodeAPIKeysFetchFailed = "api_keys_fetch_failed"
+	ErrCodeInvalidScopes      = "invalid_scopes"
+	ErrCodeTeamKeyForbidden   = "team_key_forbidden"
+	ErrCodeAPIKeyCreateFailed = "api_key_create_failed"
+	ErrCodeAPIKeyUpdateFailed = "api_key_update_failed"
+	ErrCodeAPIKeyRevokeFailed = "api_key_revoke_failed"
+	ErrCodeAPIKeyNotFound     = "api_key_not_found"
+
+	// Administration
+	ErrCodeStatsFetchFailed = "stats_fetch_failed"
+)
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// IdempotencyKeyHeader carries the client-chosen key that makes a request
+// safe to retry; IdempotentReplayedHeader marks a response served again
+// from the first attempt.
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// IdempotentRequest is the first response to a request sent with an
+// Idempotency-Key. Key is derived from the header, the user, the method
+// and the path; RequestHash is the SHA-256 of the body. Status stays zero
+// while the first attempt is still running.
+type IdempotentRequest struct {
+	Key         string    `json:"key"`
+	UserID      string    `json:"user_id"`
+	RequestHash string    `json:"request_hash"`
+	Status      int       `json:"status"`
+	ContentType string    `json:"content_type"`
+	Body        string    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
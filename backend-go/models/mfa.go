@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// MFAConfig is a user's TOTP enrollment. The secret is stored encrypted and
+// recovery codes only as SHA-256 hashes.
+type MFAConfig struct {
+	UserID             string     `json:"user_id"`
+	SecretEncrypted    string     `json:"secret_encrypted"`
+	Enabled            bool       `json:"enabled"`
+	RecoveryCodeHashes []string   `json:"recovery_code_hashes"`
+	LastUsedStep       int64      `json:"last_used_step"`
+	CreatedAt          time.Time  `json:"created_at"`
+	EnabledAt          *time.Time `json:"enabled_at,omitempty"`
+}
+
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauthUri"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// MFAChallengeResponse is returned by /auth/login instead of AuthResponse
+// when the account has 2FA enabled.
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfaRequired"`
+	MFAToken    string `json:"mfaToken"`
+}
+
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfaToken" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PetitionCost is what creating a petition debits from the balance of the
+// team owner.
+const PetitionCost = 16
+
+// PetitionStatusPending is the status of new petitions; the review flow
+// moves them on through UpdatePetitionRequest.Status.
+const PetitionStatusPending = "pending"
+
+type Petition struct {
+	ID            string          `json:"id"`
+	UserID        string          `json:"user_id"`
+	TeamID        *string         `json:"team_id"`
+	Title         string          `json:"title"`
+	Description   string          `json:"description"`
+	Content       *string         `json:"content"`
+	Category      *string         `json:"category"`
+	LegalArea     *string         `json:"legal_area"`
+	PetitionType  *string         `json:"petition_type"`
+	Target        *string         `json:"target"`
+	HasProcess    *bool           `json:"has_process"`
+	ProcessNumber *string         `json:"process_number"`
+	FormType      *string         `json:"form_type"`
+	FormAnswers   json.RawMessage `json:"form_answers,omitempty"`
+	Status        string          `json:"status"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// CreatePetitionRequest creates a petition in one of the user's teams.
+type CreatePetitionRequest struct {
+	Title         string          `json:"title" binding:"required,max=500"`
+	TeamID        string          `json:"team_id" binding:"required"`
+	Description   string          `json:"description"`
+	Content       *string         `json:"content"`
+	Category      *string         `json:"category"`
+	LegalArea     *string         `json:"legal_area"`
+	PetitionType  *string         `json:"petition_type"`
+	Target        *string         `json:"target"`
+	HasProcess    *bool           `json:"has_process"`
+	ProcessNumber *string         `json:"process_number"`
+	FormType      *string         `json:"form_type"`
+	FormAnswers   json.RawMessage `json:"form_answers"`
+}
+
+// UpdatePetitionRequest changes the editable fields of a petition; absent
+// or null fields keep their value. Owner and team cannot change.
+type UpdatePetitionRequest struct {
+	Title         *string         `json:"title" binding:"omitempty,min=1,max=500"`
+	Description   *string         `json:"description"`
+	Content       *string         `json:"content"`
+	Category      *string         `json:"category"`
+	LegalArea     *string         `json:"legal_area"`
+	PetitionType  *string         `json:"petition_type"`
+	Target        *string         `json:"target"`
+	HasProcess    *bool           `json:"has_process"`
+	ProcessNumber *string         `json:"process_number"`
+	FormType      *string         `json:"form_type"`
+	FormAnswers   json.RawMessage `json:"form_answers"`
+	Status        *string         `json:"status" binding:"omitempty,oneof=pending processing in_review review approved rejected complete payment_failed draft"`
+}
+
+// PetitionListQuery are the query parameters of GET /petitions.
+type PetitionListQuery struct {
+	Status string `form:"status"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Order  string `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+// PetitionList is a page of GET /petitions, newest first unless order=asc.
+type PetitionList struct {
+	Petitions  []Petition `json:"petitions"`
+	Total      int        `json:"total"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+	TotalPages int        `json:"totalPages"`
+}
+
+// PetitionDetail is GET /petitions/:id: the petition with its author, files
+// and comments, the same payload as the api-petitions edge function.
+type PetitionDetail struct {
+	Petition
+	// User is nil when the author's profile no longer exists.
+	User        *PetitionAuthor      `json:"user"`
+	Attachments []PetitionAttachment `json:"attachments"`
+	Comments    []PetitionComment    `json:"comments"`
+	// Documents are listed newest first.
+	Documents []PetitionDocument `json:"petition_documents"`
+}
+
+// PetitionAuthor is the part of the author's profile shown with a petition.
+type PetitionAuthor struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// PetitionComment is a comment left on a petition during review.
+type PetitionComment struct {
+	ID         string    `json:"id"`
+	PetitionID string    `json:"petition_id"`
+	AuthorID   string    `json:"author_id"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PetitionDocument is a file generated for or uploaded to a petition. Files
+// live in R2 (r2_key) or, for older uploads, in Supabase Storage
+// (storage_path).
+type PetitionDocument struct {
+	ID              string    `json:"id"`
+	PetitionID      string    `json:"petition_id"`
+	FileName        string    `json:"file_name"`
+	FilePath        string    `json:"file_path"`
+	FileType        string    `json:"file_type"`
+	FileSize        *int64    `json:"file_size"`
+	FileURL         *string   `json:"file_url"`
+	R2Key           *string   `json:"r2_key"`
+	StoragePath     *string   `json:"storage_path"`
+	StorageProvider *string   `json:"storage_provider"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PetitionAttachment is a file the user attached as evidence to a petition.
+type PetitionAttachment struct {
+	ID              string    `json:"id"`
+	PetitionID      string    `json:"petition_id"`
+	FileName        string    `json:"file_name"`
+	FileType        string    `json:"file_type"`
+	Size            *int64    `json:"size"`
+	FileURL         *string   `json:"file_url"`
+	R2Key           *string   `json:"r2_key"`
+	StoragePath     *string   `json:"storage_path"`
+	StorageProvider *string   `json:"storage_provider"`
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Profile is a row of public.profiles, created by a trigger when GoTrue
+// registers the user.
+type Profile struct {
+	ID              string     `json:"id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	IsAdmin         bool       `json:"is_admin"`
+	TermsAccepted   bool       `json:"terms_accepted"`
+	TermsAcceptedAt *time.Time `json:"terms_accepted_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// User is the profile as returned to the frontend.
+func (p *Profile) User() *User {
+	return &User{
+		ID:      p.ID,
+		Email:   p.Email,
+		Name:    p.Name,
+		IsAdmin: p.IsAdmin,
+	}
+}
@@ -0,0 +1,8 @@
+package models
+
+// UserTokens is a row of public.user_tokens, the token balance of a user.
+// Team members spend the balance of the team owner.
+type UserTokens struct {
+	UserID string `json:"user_id"`
+	Tokens int    `json:"tokens"`
+}
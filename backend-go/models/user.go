@@ -14,6 +14,7 @@ type User struct {
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	InviteID string `json:"inviteId,omitempty"`
 }
 
 type RegisterRequest struct {
@@ -21,6 +22,7 @@ type RegisterRequest struct {
 	Password      string `json:"password" binding:"required,min=6"`
 	FullName      string `json:"fullName" binding:"required"`
 	TermsAccepted bool   `json:"termsAccepted" binding:"required"`
+	InviteID      string `json:"inviteId,omitempty"`
 }
 
 type AuthResponse struct {
@@ -37,14 +39,37 @@ type ResetPasswordRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
 
+// ConfirmResetPasswordRequest completes a reset with the token from the
+// recovery e-mail: either the token hash from the link, or the e-mail plus
+// the 6-digit code.
+type ConfirmResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Email    string `json:"email" binding:"omitempty,email"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required,min=6"`
+}
+
+// ApiResponse is the envelope of every JSON response. On errors, Code is a
+// stable machine-readable code (ErrCode*), Error its message in the
+// language negotiated from Accept-Language and Fields the invalid fields of
+// the request body, if any.
 type ApiResponse struct {
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Message string      `json:"message,omitempty"`
+	Data    interface{}  `json:"data,omitempty"`
+	Code    string       `json:"code,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
+	Message string       `json:"message,omitempty"`
 }
 
+// ApiError is an error answered by Supabase (or another upstream API).
+// Code carries the PostgREST/Postgres or GoTrue error code when there is one.
 type ApiError struct {
 	Status  int
+	Code    string
 	Message string
 }
 
@@ -0,0 +1,255 @@
+package r2
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"argumentum-backend/internal/metrics"
+	"argumentum-backend/internal/tracing"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body, sent with requests that
+// carry no payload.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// unsignedPayload replaces the body hash on uploads and presigned URLs, so
+// large files need not be hashed before they are sent.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// Client talks to the Cloudflare R2 bucket holding uploaded documents,
+// signing requests with AWS Signature V4 like the r2-* edge functions do.
+type Client struct {
+	accountID       string
+	accessKeyID     string
+	secretAccessKey string
+	bucket          string
+	http            *http.Client
+}
+
+// Config holds the R2 credentials (R2_ACCOUNT_ID, R2_ACCESS_KEY_ID,
+// R2_SECRET_ACCESS_KEY and R2_BUCKET_NAME).
+type Config struct {
+	AccountID       string
+	AccessKeyID     string
+	SecretAccessKey string
+	Bucket          string
+}
+
+// NewClient returns nil when R2 is not configured.
+func NewClient(cfg Config) *Client {
+	c := &Client{
+		accountID:       cfg.AccountID,
+		accessKeyID:     cfg.AccessKeyID,
+		secretAccessKey: cfg.SecretAccessKey,
+		bucket:          cfg.Bucket,
+		http:            &http.Client{Timeout: 30 * time.Second, Transport: tracing.Transport(nil)},
+	}
+	if c.bucket == "" {
+		c.bucket = "argumentum"
+	}
+	if c.accountID == "" || c.accessKeyID == "" || c.secretAccessKey == "" {
+		return nil
+	}
+	return c
+}
+
+// DeleteObject removes the object. Deleting a key that does not exist is not
+// an error.
+func (c *Client) DeleteObject(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, "DELETE", key)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("r2: delete %s: %d %s", key, resp.StatusCode, body)
+	}
+	return nil
+}
+
+// GetObject streams the object's content. The caller closes the reader.
+func (c *Client) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("r2: get %s: %d %s", key, resp.StatusCode, body)
+	}
+	return resp.Body, nil
+}
+
+// PutObject uploads size bytes read from body. Only ctx bounds the upload:
+// large files can take longer than the client timeout.
+func (c *Client) PutObject(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path := c.path(key)
+	req, err := http.NewRequestWithContext(ctx, "PUT", "https://"+c.host()+path, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	c.sign(req, path, unsignedPayload, time.Now().UTC())
+
+	transfer := &http.Client{Transport: c.http.Transport}
+	resp, err := c.send(transfer, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("r2: put %s: %d %s", key, resp.StatusCode, body)
+	}
+	return nil
+}
+
+// PresignGet returns a URL that downloads the object without credentials
+// until ttl passes. fileName, when set, is the name the browser saves it as.
+func (c *Client) PresignGet(key string, ttl time.Duration, fileName string) string {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/auto/s3/aws4_request"
+	path := c.path(key)
+
+	params := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    c.accessKeyID + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(ttl / time.Second)),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if fileName != "" {
+		params["response-content-disposition"] = `attachment; filename="` + fileName + `"`
+	}
+	pairs := make([]string, 0, len(params))
+	for k, v := range params {
+		pairs = append(pairs, uriEncode(k, false)+"="+uriEncode(v, false))
+	}
+	sort.Strings(pairs)
+	query := strings.Join(pairs, "&")
+
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		path,
+		query,
+		"host:" + c.host(),
+		"",
+		"host",
+		unsignedPayload,
+	}, "\n")
+	signature := c.signature(now, scope, amzDate, canonicalRequest)
+	return "https://" + c.host() + path + "?" + query + "&X-Amz-Signature=" + signature
+}
+
+func (c *Client) host() string {
+	return c.bucket + "." + c.accountID + ".r2.cloudflarestorage.com"
+}
+
+func (c *Client) path(key string) string {
+	return "/" + uriEncode(strings.TrimPrefix(key, "/"), true)
+}
+
+func (c *Client) do(ctx context.Context, method, key string) (*http.Response, error) {
+	path := c.path(key)
+	req, err := http.NewRequestWithContext(ctx, method, "https://"+c.host()+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.sign(req, path, emptyPayloadHash, time.Now().UTC())
+	return c.send(c.http, req)
+}
+
+func (c *Client) send(client *http.Client, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := client.Do(req)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	metrics.ObserveUpstream("r2", req.Method, status, time.Since(start))
+	return resp, err
+}
+
+// sign adds the SigV4 Authorization header. payloadHash is the SHA-256 of the
+// body, or unsignedPayload.
+func (c *Client) sign(req *http.Request, path, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		"",
+		"host:" + c.host(),
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := now.Format("20060102") + "/auto/s3/aws4_request"
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKeyID, scope, signedHeaders, c.signature(now, scope, amzDate, canonicalRequest),
+	))
+}
+
+// signature signs a canonical request with the key derived for the day.
+func (c *Client) signature(now time.Time, scope, amzDate, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, "auto")
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// uriEncode escapes s as SigV4 requires: every byte but the unreserved
+// characters, and "/" when keepSlash is set (object keys in the path), is
+// percent-encoded.
+func uriEncode(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' ||
+			ch == '-' || ch == '_' || ch == '.' || ch == '~' || ch == '/' && keepSlash {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
+
+func hashHex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
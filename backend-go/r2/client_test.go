@@ -0,0 +1,63 @@
+package r2
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestURIEncode(t *testing.T) {
+	cases := []struct {
+		in        string
+		keepSlash bool
+		want      string
+	}{
+		{"exports/u1/e1.zip", true, "exports/u1/e1.zip"},
+		{"a b/ç+~.zip", true, "a%20b/%C3%A7%2B~.zip"},
+		{"AKID/20261017/auto/s3/aws4_request", false, "AKID%2F20261017%2Fauto%2Fs3%2Faws4_request"},
+		{`attachment; filename="a.zip"`, false, "attachment%3B%20filename%3D%22a.zip%22"},
+	}
+	for _, tc := range cases {
+		if got := uriEncode(tc.in, tc.keepSlash); got != tc.want {
+			t.Errorf("uriEncode(%q, %v) = %q, want %q", tc.in, tc.keepSlash, got, tc.want)
+		}
+	}
+}
+
+func TestPresignGet(t *testing.T) {
+	c := NewClient(Config{AccountID: "acct", AccessKeyID: "AKID", SecretAccessKey: "secret", Bucket: "bucket"})
+
+	raw := c.PresignGet("exports/u1/e 1.zip", 5*time.Minute, "dados.zip")
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != c.host() || u.EscapedPath() != "/exports/u1/e%201.zip" {
+		t.Fatalf("url = %s", raw)
+	}
+
+	q := u.Query()
+	want := map[string]string{
+		"X-Amz-Algorithm":              "AWS4-HMAC-SHA256",
+		"X-Amz-Expires":                "300",
+		"X-Amz-SignedHeaders":          "host",
+		"response-content-disposition": `attachment; filename="dados.zip"`,
+	}
+	for k, v := range want {
+		if got := q.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+	if !strings.HasPrefix(q.Get("X-Amz-Credential"), "AKID/") || !strings.HasSuffix(q.Get("X-Amz-Credential"), "/auto/s3/aws4_request") {
+		t.Errorf("X-Amz-Credential = %q", q.Get("X-Amz-Credential"))
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{64}$`).MatchString(q.Get("X-Amz-Signature")) {
+		t.Errorf("X-Amz-Signature = %q", q.Get("X-Amz-Signature"))
+	}
+	// The signature comes last, after the signed parameters
+	if !strings.Contains(raw, "&X-Amz-Signature=") || strings.Index(raw, "X-Amz-Signature") < strings.Index(raw, "response-content-disposition") {
+		t.Errorf("signature is not the last parameter: %s", raw)
+	}
+}
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// AttemptStore records attempts per key. The in-memory implementation is
+// enough for a single instance; a shared store (e.g. Redis) makes the limits
+// hold across replicas.
+type AttemptStore interface {
+	// Acquire checks every key against its policy at now and, when none has
+	// to wait, records an attempt at now on all of them, as one atomic step:
+	// parallel requests cannot all pass before any is counted. Otherwise it
+	// records nothing and returns the longest wait.
+	Acquire(ctx context.Context, now time.Time, keys ...KeyPolicy) (time.Duration, error)
+	// Release removes one attempt recorded at at for key.
+	Release(ctx context.Context, key string, at time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+// KeyPolicy is a key checked by AttemptStore.Acquire with its policy.
+type KeyPolicy struct {
+	Key    string
+	Policy Policy
+}
+
+// Policy describes how a key is throttled inside a sliding window: the first
+// FreeAttempts are not delayed, then each attempt doubles the wait from
+// BaseDelay up to MaxDelay, and LockoutAfter attempts lock the key for
+// LockoutDuration.
+type Policy struct {
+	Window          time.Duration
+	FreeAttempts    int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+}
+
+// Guard throttles an endpoint per client IP and per account (e.g. email).
+type Guard struct {
+	name      string
+	store     AttemptStore
+	byIP      Policy
+	byAccount Policy
+}
+
+func NewGuard(name string, store AttemptStore, byIP, byAccount Policy) *Guard {
+	return &Guard{name: name, store: store, byIP: byIP, byAccount: byAccount}
+}
+
+// NewLoginGuard throttles failed logins. The IP limits are looser than the
+// account limits because offices share a public IP.
+func NewLoginGuard(store AttemptStore) *Guard {
+	return NewGuard("login", store,
+		Policy{Window: 15 * time.Minute, FreeAttempts: 10, BaseDelay: time.Second, MaxDelay: 30 * time.Second, LockoutAfter: 50, LockoutDuration: 15 * time.Minute},
+		Policy{Window: 15 * time.Minute, FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 30 * time.Second, LockoutAfter: 10, LockoutDuration: 15 * time.Minute},
+	)
+}
+
+// NewPasswordResetGuard throttles reset e-mails, counting every request.
+func NewPasswordResetGuard(store AttemptStore) *Guard {
+	return NewGuard("reset", store,
+		Policy{Window: time.Hour, FreeAttempts: 5, BaseDelay: 10 * time.Second, MaxDelay: 5 * time.Minute, LockoutAfter: 20, LockoutDuration: time.Hour},
+		Policy{Window: time.Hour, FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, LockoutAfter: 5, LockoutDuration: time.Hour},
+	)
+}
+
+// Attempt is an attempt counted by Guard.Acquire. Its zero value, returned
+// when the store failed, forgives nothing.
+type Attempt struct {
+	keys []string
+	at   time.Time
+}
+
+// Acquire counts an attempt against the IP and the account (when set) and
+// returns zero, or returns how long the caller must wait before trying again
+// without counting anything. The attempt is counted up front so concurrent
+// requests see each other; Forgive takes it back when the outcome must not
+// count (a successful login, an upstream failure).
+func (g *Guard) Acquire(ctx context.Context, ip, account string) (Attempt, time.Duration, error) {
+	keys := []KeyPolicy{{Key: g.ipKey(ip), Policy: g.byIP}}
+	if account != "" {
+		keys = append(keys, KeyPolicy{Key: g.accountKey(account), Policy: g.byAccount})
+	}
+	now := time.Now()
+	wait, err := g.store.Acquire(ctx, now, keys...)
+	if err != nil || wait > 0 {
+		return Attempt{}, wait, err
+	}
+	attempt := Attempt{at: now}
+	for _, k := range keys {
+		attempt.keys = append(attempt.keys, k.Key)
+	}
+	return attempt, 0, nil
+}
+
+// Forgive removes an attempt counted by Acquire.
+func (g *Guard) Forgive(ctx context.Context, attempt Attempt) error {
+	for _, key := range attempt.keys {
+		if err := g.store.Release(ctx, key, attempt.at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reset clears the account's attempts after a successful login. The IP keeps
+// its history so one valid account cannot launder attempts on others.
+func (g *Guard) Reset(ctx context.Context, account string) error {
+	return g.store.Reset(ctx, g.accountKey(account))
+}
+
+// Wait is how long a key with the given attempts inside the window, oldest
+// first, must wait at now; zero lets the attempt through.
+func (p Policy) Wait(attempts []time.Time, now time.Time) time.Duration {
+	if len(attempts) == 0 {
+		return 0
+	}
+	last := attempts[len(attempts)-1]
+	n := len(attempts)
+
+	var until time.Time
+	switch {
+	case p.LockoutAfter > 0 && n >= p.LockoutAfter:
+		until = last.Add(p.LockoutDuration)
+	case n > p.FreeAttempts:
+		delay := p.BaseDelay << uint(n-p.FreeAttempts-1)
+		if delay > p.MaxDelay || delay <= 0 {
+			delay = p.MaxDelay
+		}
+		until = last.Add(delay)
+	default:
+		return 0
+	}
+
+	if wait := until.Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+func (g *Guard) ipKey(ip string) string {
+	return g.name + ":ip:" + ip
+}
+
+func (g *Guard) accountKey(account string) string {
+	return g.name + ":account:" + strings.ToLower(strings.TrimSpace(account))
+}
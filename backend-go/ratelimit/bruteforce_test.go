@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPolicyWait(t *testing.T) {
+	p := Policy{Window: time.Hour, FreeAttempts: 2, BaseDelay: time.Second, MaxDelay: 4 * time.Second, LockoutAfter: 6, LockoutDuration: time.Hour}
+	now := time.Now()
+	attempts := func(n int) []time.Time {
+		times := make([]time.Time, n)
+		for i := range times {
+			times[i] = now
+		}
+		return times
+	}
+
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{"no attempts", 0, 0},
+		{"free attempts", 2, 0},
+		{"first delay", 3, time.Second},
+		{"doubles", 4, 2 * time.Second},
+		{"capped at max delay", 5, 4 * time.Second},
+		{"lockout", 6, time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Wait(attempts(tt.attempts), now); got != tt.want {
+				t.Errorf("Wait = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := p.Wait(attempts(3), now.Add(2*time.Second)); got != 0 {
+		t.Errorf("Wait after the delay = %v, want 0", got)
+	}
+}
+
+func newTestGuard() *Guard {
+	return NewGuard("test", NewMemoryAttemptStore(time.Hour),
+		Policy{Window: time.Hour, FreeAttempts: 5, BaseDelay: time.Minute, MaxDelay: time.Hour},
+		Policy{Window: time.Hour, FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour},
+	)
+}
+
+func TestGuardThrottlesAccountAndIP(t *testing.T) {
+	g, ctx := newTestGuard(), context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, wait, _ := g.Acquire(ctx, "10.0.0.1", "a@example.com"); wait != 0 {
+			t.Fatalf("attempt %d waited %v", i+1, wait)
+		}
+	}
+	if _, wait, _ := g.Acquire(ctx, "10.0.0.2", "A@example.com "); wait == 0 {
+		t.Fatal("account limit was bypassed by changing IP and case")
+	}
+	// Other accounts from the same IP pass until the IP uses its free
+	// attempts, plus the one that first exceeds them
+	for _, account := range []string{"b@example.com", "c@example.com", "d@example.com"} {
+		if _, wait, _ := g.Acquire(ctx, "10.0.0.1", account); wait != 0 {
+			t.Fatalf("%s waited %v", account, wait)
+		}
+	}
+	if _, wait, _ := g.Acquire(ctx, "10.0.0.1", "e@example.com"); wait == 0 {
+		t.Fatal("IP limit was bypassed by changing account")
+	}
+}
+
+func TestGuardForgiveAndReset(t *testing.T) {
+	g, ctx := newTestGuard(), context.Background()
+
+	// Successful attempts given back never add up
+	for i := 0; i < 10; i++ {
+		attempt, wait, _ := g.Acquire(ctx, "10.0.0.1", "a@example.com")
+		if wait != 0 {
+			t.Fatalf("attempt %d waited %v", i+1, wait)
+		}
+		if err := g.Forgive(ctx, attempt); err != nil {
+			t.Fatalf("Forgive: %v", err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		g.Acquire(ctx, "10.0.0.1", "a@example.com")
+	}
+	if err := g.Reset(ctx, "a@example.com"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if _, wait, _ := g.Acquire(ctx, "10.0.0.1", "a@example.com"); wait != 0 {
+		t.Fatalf("after Reset waited %v", wait)
+	}
+
+	// Forgiving the zero Attempt of a failed store is a no-op
+	if err := g.Forgive(ctx, Attempt{}); err != nil {
+		t.Fatalf("Forgive(zero): %v", err)
+	}
+}
+
+func TestGuardParallelBurst(t *testing.T) {
+	g, ctx := newTestGuard(), context.Background()
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, wait, _ := g.Acquire(ctx, "10.0.0.1", "a@example.com"); wait == 0 {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// FreeAttempts pass, plus the one that first exceeds them; the rest of
+	// the burst already sees those counted
+	if got := allowed.Load(); got != 3 {
+		t.Fatalf("%d attempts of a parallel burst passed, want 3", got)
+	}
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit is a token bucket: it holds up to Burst requests and refills Burst
+// tokens every Period, so short bursts pass while the average rate stays
+// at Burst per Period.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Rate is how many tokens the bucket gains per second.
+func (l Limit) Rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// Take is the outcome of taking one token from a bucket.
+type Take struct {
+	Allowed bool
+	// Remaining is the number of whole tokens left after this request.
+	Remaining int
+	// Reset is how long until the bucket is full again; RetryAfter how long
+	// until the next token when the request was refused.
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// BucketStore keeps the token buckets. The in-memory implementation is
+// enough for a single instance; a shared store (e.g. Redis with a Lua
+// script) makes the quotas hold across replicas.
+type BucketStore interface {
+	// Take refills the bucket at key up to now and takes one token from it
+	// when available.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Take, error)
+}
+
+// Class groups routes with the same cost.
+type Class string
+
+const (
+	ClassRead   Class = "read"
+	ClassWrite  Class = "write"
+	ClassUpload Class = "upload"
+)
+
+// Quota holds the limits of a route class for each subject a request
+// counts against. A zero Limit leaves that subject unlimited.
+type Quota struct {
+	User   Limit
+	Team   Limit
+	APIKey Limit
+}
+
+// DefaultQuotas are the limits per route class. Writes include the
+// token-charging routes, so they are much tighter than reads; teams get
+// room for several members working at once.
+func DefaultQuotas() map[Class]Quota {
+	return map[Class]Quota{
+		ClassRead: {
+			User:   Limit{Burst: 300, Period: time.Minute},
+			Team:   Limit{Burst: 1000, Period: time.Minute},
+			APIKey: Limit{Burst: 120, Period: time.Minute},
+		},
+		ClassWrite: {
+			User:   Limit{Burst: 60, Period: time.Minute},
+			Team:   Limit{Burst: 200, Period: time.Minute},
+			APIKey: Limit{Burst: 30, Period: time.Minute},
+		},
+		ClassUpload: {
+			User:   Limit{Burst: 10, Period: time.Minute},
+			Team:   Limit{Burst: 40, Period: time.Minute},
+			APIKey: Limit{Burst: 5, Period: time.Minute},
+		},
+	}
+}
+
+// Subject identifies who a request counts against. TeamIDs are the teams
+// whose quota the request shares: the team of a team-scoped API key, the team
+// the route addresses, or else every team of the user. APIKeyID is empty for
+// interactive sessions.
+type Subject struct {
+	UserID   string
+	TeamIDs  []string
+	APIKeyID string
+}
+
+// Decision is the result for the most constrained bucket of a request,
+// which is what the RateLimit-* headers report.
+type Decision struct {
+	Take
+	Limit Limit
+}
+
+// Limiter applies the class quotas to a request's subjects.
+type Limiter struct {
+	store  BucketStore
+	quotas map[Class]Quota
+}
+
+func NewLimiter(store BucketStore, quotas map[Class]Quota) *Limiter {
+	return &Limiter{store: store, quotas: quotas}
+}
+
+// Allow takes a token from the API key, team and user buckets, in that
+// order, stopping at the first that is empty. ok is false when the class
+// has no limit for any of the subjects.
+func (l *Limiter) Allow(ctx context.Context, class Class, s Subject) (d Decision, ok bool, err error) {
+	quota := l.quotas[class]
+	type bucket struct {
+		kind, id string
+		limit    Limit
+	}
+	buckets := []bucket{{"key", s.APIKeyID, quota.APIKey}}
+	for _, teamID := range s.TeamIDs {
+		buckets = append(buckets, bucket{"team", teamID, quota.Team})
+	}
+	buckets = append(buckets, bucket{"user", s.UserID, quota.User})
+
+	now := time.Now()
+	for _, b := range buckets {
+		if b.id == "" || b.limit.Burst <= 0 || b.limit.Period <= 0 {
+			continue
+		}
+		take, err := l.store.Take(ctx, string(class)+":"+b.kind+":"+b.id, b.limit, now)
+		if err != nil {
+			return Decision{}, false, err
+		}
+		// Report the bucket closest to running out
+		if !ok || !take.Allowed || fraction(take.Remaining, b.limit) < fraction(d.Remaining, d.Limit) {
+			d, ok = Decision{Take: take, Limit: b.limit}, true
+		}
+		if !take.Allowed {
+			break
+		}
+	}
+	return d, ok, nil
+}
+
+func fraction(remaining int, limit Limit) float64 {
+	return float64(remaining) / float64(limit.Burst)
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryBucketStoreRefills(t *testing.T) {
+	store := NewMemoryBucketStore(time.Hour)
+	limit := Limit{Burst: 2, Period: 2 * time.Second}
+	ctx, now := context.Background(), time.Now()
+
+	for i, want := range []int{1, 0} {
+		take, _ := store.Take(ctx, "k", limit, now)
+		if !take.Allowed || take.Remaining != want {
+			t.Fatalf("take %d = %+v, want allowed with %d remaining", i, take, want)
+		}
+	}
+	take, _ := store.Take(ctx, "k", limit, now)
+	if take.Allowed || take.RetryAfter != time.Second {
+		t.Fatalf("empty bucket = %+v, want refused with 1s retry", take)
+	}
+
+	// One token per second comes back
+	take, _ = store.Take(ctx, "k", limit, now.Add(time.Second))
+	if !take.Allowed || take.Remaining != 0 || take.Reset != 2*time.Second {
+		t.Fatalf("after 1s = %+v, want allowed, 0 remaining, 2s reset", take)
+	}
+}
+
+func TestLimiterStopsAtTightestBucket(t *testing.T) {
+	limiter := NewLimiter(NewMemoryBucketStore(time.Hour), map[Class]Quota{
+		ClassWrite: {
+			User:   Limit{Burst: 10, Period: time.Minute},
+			APIKey: Limit{Burst: 1, Period: time.Minute},
+		},
+	})
+	ctx := context.Background()
+	withKey := Subject{UserID: "u1", APIKeyID: "key-1"}
+
+	d, ok, _ := limiter.Allow(ctx, ClassWrite, withKey)
+	if !ok || !d.Allowed || d.Limit.Burst != 1 {
+		t.Fatalf("first request = %+v, want allowed and reported against the key", d)
+	}
+	d, _, _ = limiter.Allow(ctx, ClassWrite, withKey)
+	if d.Allowed {
+		t.Fatal("second request with the key was allowed")
+	}
+
+	// The refused request did not reach the user bucket: 10 - 1 remain
+	d, _, _ = limiter.Allow(ctx, ClassWrite, Subject{UserID: "u1"})
+	if !d.Allowed || d.Remaining != 8 {
+		t.Fatalf("session request = %+v, want allowed with 8 remaining", d)
+	}
+
+	if _, ok, _ := limiter.Allow(ctx, ClassRead, withKey); ok {
+		t.Fatal("class without quota was limited")
+	}
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// MemoryAttemptStore keeps attempts in process memory. Entries older than
+// maxAge are dropped on write.
+type MemoryAttemptStore struct {
+	mu        sync.Mutex
+	maxAge    time.Duration
+	attempts  map[string][]time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryAttemptStore(maxAge time.Duration) *MemoryAttemptStore {
+	return &MemoryAttemptStore{maxAge: maxAge, attempts: make(map[string][]time.Time)}
+}
+
+func (s *MemoryAttemptStore) Acquire(ctx context.Context, now time.Time, keys ...KeyPolicy) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var wait time.Duration
+	for _, k := range keys {
+		since := now.Add(-k.Policy.Window)
+		var recent []time.Time
+		for _, at := range s.attempts[k.Key] {
+			if at.After(since) {
+				recent = append(recent, at)
+			}
+		}
+		if w := k.Policy.Wait(recent, now); w > wait {
+			wait = w
+		}
+	}
+	if wait > 0 {
+		return wait, nil
+	}
+	for _, k := range keys {
+		s.record(k.Key, now)
+	}
+	return 0, nil
+}
+
+func (s *MemoryAttemptStore) Release(ctx context.Context, key string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	times := s.attempts[key]
+	for i, t := range times {
+		if t.Equal(at) {
+			s.attempts[key] = append(times[:i], times[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *MemoryAttemptStore) record(key string, at time.Time) {
+	cutoff := at.Add(-s.maxAge)
+	kept := s.attempts[key][:0]
+	for _, t := range s.attempts[key] {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	s.attempts[key] = append(kept, at)
+
+	if at.Sub(s.lastSweep) > s.maxAge {
+		for k, times := range s.attempts {
+			if len(times) == 0 || !times[len(times)-1].After(cutoff) {
+				delete(s.attempts, k)
+			}
+		}
+		s.lastSweep = at
+	}
+}
+
+func (s *MemoryAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.attempts, key)
+	return nil
+}
+
+// MemoryBucketStore keeps token buckets in process memory. Buckets idle for
+// longer than maxIdle are full again and are dropped on write.
+type MemoryBucketStore struct {
+	mu        sync.Mutex
+	maxIdle   time.Duration
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func NewMemoryBucketStore(maxIdle time.Duration) *MemoryBucketStore {
+	return &MemoryBucketStore{maxIdle: maxIdle, buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryBucketStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Take, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	burst, rate := float64(limit.Burst), limit.Rate()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		s.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*rate)
+		b.updated = now
+	}
+
+	var take Take
+	if b.tokens >= 1 {
+		b.tokens--
+		take.Allowed = true
+	} else {
+		take.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	take.Remaining = int(b.tokens)
+	take.Reset = seconds((burst - b.tokens) / rate)
+
+	if now.Sub(s.lastSweep) > s.maxIdle {
+		for k, b := range s.buckets {
+			if now.Sub(b.updated) > s.maxIdle {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	return take, nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
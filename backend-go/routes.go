@@ -0,0 +1,148 @@
+package main
+
+import (
+	"time"
+
+	"argumentum-backend/handlers"
+	"argumentum-backend/internal/metrics"
+	"argumentum-backend/internal/openapi"
+	"argumentum-backend/middleware"
+	"argumentum-backend/models"
+	"argumentum-backend/ratelimit"
+	"argumentum-backend/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// server holds the handlers and authentication middleware the routes are
+// bound to. Keeping the route table out of main lets tests build the same
+// router without any backing service.
+type server struct {
+	auth      *handlers.AuthHandler
+	petitions *handlers.PetitionHandler
+	profile   *handlers.ProfileHandler
+	storage   *handlers.StorageHandler
+	admin     *handlers.AdminHandler
+	health    *handlers.HealthHandler
+
+	// requireAuth também aceita chaves de API (limitadas por RequireScope);
+	// requireSession exige um login interativo
+	requireAuth    gin.HandlerFunc
+	requireSession gin.HandlerFunc
+	idempotency    store.IdempotencyStore
+	limiter        *ratelimit.Limiter
+	metricsToken   string
+	// longRequestTimeout replaces the server timeouts on file transfers.
+	longRequestTimeout time.Duration
+}
+
+// routes registers every endpoint on r. Each one must also be described in
+// apiSpec; routes_test.go fails otherwise.
+func (s *server) routes(r *gin.Engine) {
+	// Cotas por chave de API, equipe e usuário, por classe de rota
+	read := middleware.RateLimit(s.limiter, ratelimit.ClassRead)
+	write := middleware.RateLimit(s.limiter, ratelimit.ClassWrite)
+	upload := middleware.RateLimit(s.limiter, ratelimit.ClassUpload)
+
+	// Envio e download de arquivos não cabem nos timeouts do servidor
+	long := middleware.LongRequest(s.longRequestTimeout)
+
+	// Repetições com a mesma Idempotency-Key recebem a primeira resposta
+	idempotent := middleware.Idempotency(s.idempotency, 24*time.Hour)
+
+	// Auth routes (public)
+	auth := r.Group("/auth")
+	{
+		auth.POST("/login", s.auth.Login)
+		auth.POST("/login/mfa", s.auth.LoginMFA)
+		auth.POST("/register", s.auth.Register)
+		auth.POST("/logout", s.requireSession, s.auth.Logout)
+		auth.POST("/logout/all", s.requireSession, s.auth.LogoutAll)
+		auth.GET("/sessions", s.requireSession, s.auth.ListSessions)
+		auth.DELETE("/sessions/:id", s.requireSession, s.auth.DeleteSession)
+		auth.POST("/mfa/enroll", s.requireSession, s.auth.EnrollMFA)
+		auth.POST("/mfa/verify", s.requireSession, s.auth.VerifyMFA)
+		auth.POST("/mfa/disable", s.requireSession, s.auth.DisableMFA)
+		auth.POST("/refresh", s.auth.RefreshToken)
+		auth.POST("/reset-password", s.auth.ResetPassword)
+		auth.POST("/reset-password/confirm", s.auth.ConfirmResetPassword)
+		auth.PUT("/password", s.requireSession, s.auth.ChangePassword)
+	}
+
+	// Chaves públicas para validação dos tokens por outros serviços
+	r.GET("/.well-known/jwks.json", s.auth.JWKS)
+
+	// Comprovante de exclusão de conta, acessível com o receiptToken
+	r.GET("/account-deletions/:id/receipt", s.auth.GetDeletionReceipt)
+
+	// Download da exportação de dados pelo link assinado
+	r.GET("/exports/:id/download", s.profile.DownloadDataExport)
+
+	// Routes open to API keys: each one names the scope a key needs
+	integrations := r.Group("/")
+	integrations.Use(s.requireAuth)
+	{
+		scope := middleware.RequireScope
+
+		integrations.GET("/profile", read, scope(models.ScopeProfileRead), s.profile.GetProfile)
+
+		integrations.GET("/petitions", read, scope(models.ScopePetitionsRead), s.petitions.GetPetitions)
+		integrations.POST("/petitions", write, scope(models.ScopePetitionsWrite), idempotent, s.petitions.CreatePetition)
+		integrations.GET("/petitions/:id", read, scope(models.ScopePetitionsRead), s.petitions.GetPetitionByID)
+		integrations.PUT("/petitions/:id", write, scope(models.ScopePetitionsWrite), s.petitions.UpdatePetition)
+		integrations.DELETE("/petitions/:id", write, scope(models.ScopePetitionsWrite), s.petitions.DeletePetition)
+
+		integrations.GET("/teams", read, scope(models.ScopeTeamsRead), s.petitions.GetTeams)
+		integrations.GET("/teams/:id", read, scope(models.ScopeTeamsRead), middleware.RequireTeamRole("id"), s.petitions.GetTeamByID)
+		integrations.GET("/teams/:id/token-balance", read, scope(models.ScopeTeamsRead), middleware.RequireTeamRole("id"), s.petitions.GetTeamTokenBalance)
+
+		integrations.GET("/documents", read, scope(models.ScopeDocumentsRead), s.storage.GetDocuments)
+		integrations.POST("/documents/upload", long, upload, scope(models.ScopeDocumentsWrite), s.storage.UploadDocument)
+		integrations.DELETE("/documents/:id", write, scope(models.ScopeDocumentsWrite), s.storage.DeleteDocument)
+
+		integrations.GET("/petition-settings", read, scope(models.ScopePetitionsRead), s.petitions.GetPetitionSettings)
+		integrations.PUT("/petition-settings", write, scope(models.ScopePetitionsWrite), s.petitions.UpdatePetitionSettings)
+
+		integrations.POST("/storage/signed-url", read, scope(models.ScopeDocumentsRead), s.storage.GetSignedURL)
+		integrations.POST("/storage/delete", write, scope(models.ScopeDocumentsWrite), s.storage.DeleteFile)
+	}
+
+	// Protected routes (interactive sessions only)
+	protected := r.Group("/")
+	protected.Use(s.requireSession)
+	{
+		protected.PUT("/profile", write, s.profile.UpdateProfile)
+		protected.DELETE("/profile", write, s.auth.RequestAccountDeletion)
+		protected.GET("/profile/deletion", read, s.auth.GetAccountDeletion)
+		protected.DELETE("/profile/deletion", write, s.auth.CancelAccountDeletion)
+		protected.POST("/profile/export", write, idempotent, s.profile.RequestDataExport)
+		protected.GET("/profile/export/:id", read, s.profile.GetDataExport)
+
+		protected.POST("/teams", write, idempotent, s.petitions.CreateTeam)
+		protected.PUT("/teams/:id", write, middleware.RequireTeamRole("id", models.TeamRoleOwner), s.petitions.UpdateTeam)
+		protected.DELETE("/teams/:id", write, middleware.RequireTeamRole("id", models.TeamRoleOwner), s.petitions.DeleteTeam)
+
+		protected.GET("/api-keys", read, s.auth.ListAPIKeys)
+		protected.POST("/api-keys", write, s.auth.CreateAPIKey)
+		protected.PUT("/api-keys/:id", write, s.auth.UpdateAPIKey)
+		protected.DELETE("/api-keys/:id", write, s.auth.DeleteAPIKey)
+	}
+
+	// Admin routes
+	admin := protected.Group("/admin")
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.GET("/stats", read, s.admin.GetStats)
+	}
+
+	// Métricas Prometheus (protegidas por METRICS_TOKEN, se definido)
+	r.GET("/metrics", middleware.MetricsAuth(s.metricsToken), gin.WrapH(metrics.Handler()))
+
+	r.GET("/health", s.health.Live)
+	r.GET("/health/live", s.health.Live)
+	r.GET("/health/ready", s.health.Ready)
+
+	// Especificação OpenAPI e documentação interativa (Swagger UI)
+	r.GET("/openapi.json", gin.WrapH(openapi.JSONHandler(apiSpec())))
+	r.GET("/docs/*filepath", gin.WrapH(openapi.DocsHandler("/docs/", "/openapi.json")))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"argumentum-backend/handlers"
+	"argumentum-backend/internal/openapi"
+	"argumentum-backend/ratelimit"
+	"argumentum-backend/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func testRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	pass := func(c *gin.Context) { c.Next() }
+
+	r := gin.New()
+	app := &server{
+		auth:           &handlers.AuthHandler{},
+		petitions:      &handlers.PetitionHandler{},
+		profile:        &handlers.ProfileHandler{},
+		storage:        &handlers.StorageHandler{},
+		admin:          &handlers.AdminHandler{},
+		health:         &handlers.HealthHandler{},
+		requireAuth:    pass,
+		requireSession: pass,
+		idempotency:    store.NewMemoryIdempotencyStore(),
+		limiter:        ratelimit.NewLimiter(ratelimit.NewMemoryBucketStore(time.Minute), ratelimit.DefaultQuotas()),
+	}
+	app.routes(r)
+	return r
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	spec := apiSpec()
+	for _, route := range testRouter().Routes() {
+		if !spec.Has(route.Method, route.Path) {
+			t.Errorf("%s %s is registered but missing from apiSpec", route.Method, route.Path)
+		}
+	}
+}
+
+func TestEveryDocumentedRouteExists(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, route := range testRouter().Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for _, op := range apiSpec().Operations() {
+		if !registered[op] {
+			t.Errorf("%s is documented but not registered", op)
+		}
+	}
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	w := httptest.NewRecorder()
+	testRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	var doc openapi.Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("openapi = %q, want %q", doc.OpenAPI, openapi.Version)
+	}
+	for _, name := range []string{"LoginRequest", "AuthResponse", "ApiResponse", "FieldError"} {
+		if doc.Components.Schemas[name] == nil {
+			t.Errorf("schema %s missing from components", name)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// AccountDeletionStore persists LGPD erasure requests.
+type AccountDeletionStore interface {
+	Create(ctx context.Context, deletion *models.AccountDeletion) error
+	Get(ctx context.Context, id string) (*models.AccountDeletion, error)
+	// GetPending returns the user's pending request, or ErrNotFound.
+	GetPending(ctx context.Context, userID string) (*models.AccountDeletion, error)
+	// ListDue returns pending requests whose grace period ended before t.
+	ListDue(ctx context.Context, t time.Time) ([]models.AccountDeletion, error)
+	// Claim leases a pending request to the caller until the given time. It
+	// reports false when the request is no longer pending or another worker
+	// holds an unexpired lease.
+	Claim(ctx context.Context, id string, now, until time.Time) (bool, error)
+	// Cancel marks a pending request cancelled at now, under the same
+	// condition as Claim: it reports false when the request is no longer
+	// pending or a worker holds an unexpired lease on it.
+	Cancel(ctx context.Context, id string, now time.Time) (bool, error)
+	// Update saves status, timestamps, receipt and last error.
+	Update(ctx context.Context, deletion *models.AccountDeletion) error
+}
+
+func NewAccountDeletionStore(db *gateway.Client, memory bool) AccountDeletionStore {
+	if memory {
+		slog.Warn("Usando armazenamento de pedidos de exclusão em memória")
+		return NewMemoryAccountDeletionStore()
+	}
+	return NewSupabaseAccountDeletionStore(db)
+}
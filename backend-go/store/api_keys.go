@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// APIKeyStore persists API keys. Revoked keys are kept for auditing and
+// left out of listings.
+type APIKeyStore interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	Get(ctx context.Context, id string) (*models.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	ListByUser(ctx context.Context, userID string) ([]models.APIKey, error)
+	// Update saves the key's name and scopes.
+	Update(ctx context.Context, key *models.APIKey) error
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+	Revoke(ctx context.Context, id string, at time.Time) error
+}
+
+func NewAPIKeyStore(db *gateway.Client, memory bool) APIKeyStore {
+	if memory {
+		slog.Warn("Usando armazenamento de chaves de API em memória")
+		return NewMemoryAPIKeyStore()
+	}
+	return NewSupabaseAPIKeyStore(db)
+}
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// DataExportStore persists LGPD data exports.
+type DataExportStore interface {
+	Create(ctx context.Context, export *models.DataExport) error
+	Get(ctx context.Context, id string) (*models.DataExport, error)
+	// GetActive returns the user's pending or processing export, or ErrNotFound.
+	GetActive(ctx context.Context, userID string) (*models.DataExport, error)
+	// ListByUser returns all of the user's exports, whatever their status.
+	ListByUser(ctx context.Context, userID string) ([]models.DataExport, error)
+	// ListExpired returns exports whose file may be removed at t.
+	ListExpired(ctx context.Context, t time.Time) ([]models.DataExport, error)
+	// Update saves status, file, size, error and completion time.
+	Update(ctx context.Context, export *models.DataExport) error
+	Delete(ctx context.Context, id string) error
+}
+
+func NewDataExportStore(db *gateway.Client, memory bool) DataExportStore {
+	if memory {
+		slog.Warn("Usando armazenamento de exportações em memória")
+		return NewMemoryDataExportStore()
+	}
+	return NewSupabaseDataExportStore(db)
+}
@@ -0,0 +1,15 @@
+package store
+
+import (
+	"context"
+
+	"argumentum-backend/models"
+)
+
+// DocumentStore reads the files and comments attached to petitions.
+type DocumentStore interface {
+	ListDocuments(ctx context.Context, petitionIDs ...string) ([]models.PetitionDocument, error)
+	ListAttachments(ctx context.Context, petitionIDs ...string) ([]models.PetitionAttachment, error)
+	// ListComments returns the comments on the petitions, oldest first.
+	ListComments(ctx context.Context, petitionIDs ...string) ([]models.PetitionComment, error)
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// IdempotencyStore keeps the first response of requests sent with an
+// Idempotency-Key until they expire.
+type IdempotencyStore interface {
+	// Reserve saves req as in flight. When a live entry already holds the
+	// key it is returned instead and nothing is saved.
+	Reserve(ctx context.Context, req *models.IdempotentRequest) (*models.IdempotentRequest, error)
+	// Complete records the response of a reserved key.
+	Complete(ctx context.Context, key string, status int, contentType, body string) error
+	// TakeOver hands the entry at req.Key to req when it is still in flight
+	// and was reserved before staleBefore, its first attempt presumed dead.
+	// Of concurrent retries, only one gets true.
+	TakeOver(ctx context.Context, req *models.IdempotentRequest, staleBefore time.Time) (bool, error)
+	// Release drops a reservation, so the request can be tried again.
+	Release(ctx context.Context, key string) error
+	// DeleteExpired removes the entries that expired before t.
+	DeleteExpired(ctx context.Context, t time.Time) error
+}
+
+func NewIdempotencyStore(db *gateway.Client, memory bool) IdempotencyStore {
+	if memory {
+		slog.Warn("Usando armazenamento de chaves de idempotência em memória")
+		return NewMemoryIdempotencyStore()
+	}
+	return NewSupabaseIdempotencyStore(db)
+}
+
+// RunIdempotencyCleanup deletes expired entries every interval until ctx is
+// cancelled.
+func RunIdempotencyCleanup(ctx context.Context, s IdempotencyStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.DeleteExpired(context.WithoutCancel(ctx), time.Now()); err != nil {
+				slog.ErrorContext(ctx, "Error deleting expired idempotency keys", "error", err)
+			}
+		}
+	}
+}
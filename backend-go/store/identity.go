@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidCredentials is returned by IdentityStore.SignIn when the e-mail
+// or password is wrong, as opposed to the provider being unavailable.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// IdentityStore holds login credentials. In production this is GoTrue; the
+// in-memory implementation lets the API run without a Supabase project.
+type IdentityStore interface {
+	// SignIn checks the credentials and returns the user id.
+	SignIn(ctx context.Context, email, password string) (string, error)
+	// SignUp creates the account and its profile. metadata carries the
+	// profile fields (full_name, terms_accepted, terms_accepted_at).
+	SignUp(ctx context.Context, email, password string, metadata map[string]interface{}) (string, error)
+	// SendRecovery e-mails a password recovery link and code.
+	SendRecovery(ctx context.Context, email string) error
+	// VerifyRecovery consumes a recovery token and returns the user id. With
+	// an e-mail the token is the 6-digit code, otherwise the link's hash.
+	VerifyRecovery(ctx context.Context, email, token string) (string, error)
+	SetPassword(ctx context.Context, userID, password string) error
+	// GetEmail returns the login e-mail; profiles.email may be empty.
+	GetEmail(ctx context.Context, userID string) (string, error)
+	// DeleteUser removes the account and its profile. It undoes a
+	// registration that could not be completed.
+	DeleteUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"argumentum-backend/models"
+)
+
+type MemoryAccountDeletionStore struct {
+	mu        sync.Mutex
+	deletions map[string]models.AccountDeletion
+}
+
+func NewMemoryAccountDeletionStore() *MemoryAccountDeletionStore {
+	return &MemoryAccountDeletionStore{deletions: make(map[string]models.AccountDeletion)}
+}
+
+func (s *MemoryAccountDeletionStore) Create(ctx context.Context, deletion *models.AccountDeletion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deletions[deletion.ID] = *deletion
+	return nil
+}
+
+func (s *MemoryAccountDeletionStore) Get(ctx context.Context, id string) (*models.AccountDeletion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deletion, ok := s.deletions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &deletion, nil
+}
+
+func (s *MemoryAccountDeletionStore) GetPending(ctx context.Context, userID string) (*models.AccountDeletion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, deletion := range s.deletions {
+		if deletion.UserID == userID && deletion.Status == models.AccountDeletionPending {
+			return &deletion, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryAccountDeletionStore) ListDue(ctx context.Context, t time.Time) ([]models.AccountDeletion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []models.AccountDeletion
+	for _, deletion := range s.deletions {
+		if deletion.Status == models.AccountDeletionPending && deletion.ScheduledFor.Before(t) {
+			due = append(due, deletion)
+		}
+	}
+	return due, nil
+}
+
+func (s *MemoryAccountDeletionStore) Claim(ctx context.Context, id string, now, until time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deletion, ok := s.deletions[id]
+	if !ok || deletion.Status != models.AccountDeletionPending {
+		return false, nil
+	}
+	if deletion.LockedUntil != nil && deletion.LockedUntil.After(now) {
+		return false, nil
+	}
+	deletion.LockedUntil = &until
+	s.deletions[id] = deletion
+	return true, nil
+}
+
+func (s *MemoryAccountDeletionStore) Cancel(ctx context.Context, id string, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deletion, ok := s.deletions[id]
+	if !ok || deletion.Status != models.AccountDeletionPending {
+		return false, nil
+	}
+	if deletion.LockedUntil != nil && deletion.LockedUntil.After(now) {
+		return false, nil
+	}
+	deletion.Status = models.AccountDeletionCancelled
+	deletion.CancelledAt = &now
+	s.deletions[id] = deletion
+	return true, nil
+}
+
+func (s *MemoryAccountDeletionStore) Update(ctx context.Context, deletion *models.AccountDeletion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.deletions[deletion.ID]; !ok {
+		return ErrNotFound
+	}
+	s.deletions[deletion.ID] = *deletion
+	return nil
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"argumentum-backend/models"
+)
+
+type MemoryAPIKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]models.APIKey
+}
+
+func NewMemoryAPIKeyStore() *MemoryAPIKeyStore {
+	return &MemoryAPIKeyStore{keys: make(map[string]models.APIKey)}
+}
+
+func (s *MemoryAPIKeyStore) Create(ctx context.Context, key *models.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.ID] = copyAPIKey(*key)
+	return nil
+}
+
+func (s *MemoryAPIKeyStore) Get(ctx context.Context, id string) (*models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	key = copyAPIKey(key)
+	return &key, nil
+}
+
+func (s *MemoryAPIKeyStore) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range s.keys {
+		if key.KeyHash == hash {
+			key = copyAPIKey(key)
+			return &key, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryAPIKeyStore) ListByUser(ctx context.Context, userID string) ([]models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []models.APIKey
+	for _, key := range s.keys {
+		if key.UserID == userID && key.RevokedAt == nil {
+			keys = append(keys, copyAPIKey(key))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (s *MemoryAPIKeyStore) Update(ctx context.Context, key *models.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.keys[key.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	stored.Name = key.Name
+	stored.Scopes = append([]string(nil), key.Scopes...)
+	s.keys[key.ID] = stored
+	return nil
+}
+
+func (s *MemoryAPIKeyStore) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.keys[id]; ok {
+		key.LastUsedAt = &at
+		s.keys[id] = key
+	}
+	return nil
+}
+
+func (s *MemoryAPIKeyStore) Revoke(ctx context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.keys[id]; ok && key.RevokedAt == nil {
+		key.RevokedAt = &at
+		s.keys[id] = key
+	}
+	return nil
+}
+
+func copyAPIKey(key models.APIKey) models.APIKey {
+	key.Scopes = append([]string(nil), key.Scopes...)
+	return key
+}
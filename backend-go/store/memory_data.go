@@ -0,0 +1,129 @@
+package store
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"argumentum-backend/models"
+	"argumentum-backend/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memoryData is the dataset shared by the in-memory repositories, so that an
+// account created by the identity store shows up as a profile and accepting
+// an invite adds a team member, as they would in Postgres.
+type memoryData struct {
+	mu sync.Mutex
+
+	accounts    map[string]memoryAccount
+	profiles    map[string]models.Profile
+	petitions   map[string]models.Petition
+	teams       map[string]models.Team
+	members     map[string]models.TeamMember
+	invites     map[string]models.TeamInvite
+	tokens      map[string]int
+	documents   map[string]models.PetitionDocument
+	attachments map[string]models.PetitionAttachment
+	comments    map[string]models.PetitionComment
+	recoveries  map[string]memoryRecovery
+}
+
+// memoryAccount is the GoTrue side of a user: login e-mail and password hash.
+type memoryAccount struct {
+	Email        string
+	PasswordHash []byte
+}
+
+func newMemoryData(seed *Seed) (*memoryData, error) {
+	d := &memoryData{
+		accounts:    make(map[string]memoryAccount),
+		profiles:    make(map[string]models.Profile),
+		petitions:   make(map[string]models.Petition),
+		teams:       make(map[string]models.Team),
+		members:     make(map[string]models.TeamMember),
+		invites:     make(map[string]models.TeamInvite),
+		tokens:      make(map[string]int),
+		documents:   make(map[string]models.PetitionDocument),
+		attachments: make(map[string]models.PetitionAttachment),
+		comments:    make(map[string]models.PetitionComment),
+		recoveries:  make(map[string]memoryRecovery),
+	}
+	if seed == nil {
+		return d, nil
+	}
+
+	now := time.Now().UTC()
+	for _, u := range seed.Users {
+		hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		d.accounts[u.ID] = memoryAccount{Email: strings.ToLower(u.Email), PasswordHash: hash}
+		profile := u.Profile
+		stamp(&profile.CreatedAt, &profile.UpdatedAt, now)
+		d.profiles[u.ID] = profile
+	}
+	for _, t := range seed.Teams {
+		stamp(&t.CreatedAt, &t.UpdatedAt, now)
+		d.teams[t.ID] = t
+	}
+	for _, m := range seed.TeamMembers {
+		if m.ID == "" {
+			m.ID = utils.GenerateID()
+		}
+		stamp(&m.CreatedAt, &m.UpdatedAt, now)
+		d.members[m.ID] = m
+	}
+	for _, i := range seed.TeamInvites {
+		if i.CreatedAt.IsZero() {
+			i.CreatedAt = now
+		}
+		d.invites[i.ID] = i
+	}
+	for _, t := range seed.Tokens {
+		d.tokens[t.UserID] = t.Tokens
+	}
+	for _, p := range seed.Petitions {
+		stamp(&p.CreatedAt, &p.UpdatedAt, now)
+		d.petitions[p.ID] = p
+	}
+	for _, doc := range seed.Documents {
+		stamp(&doc.CreatedAt, &doc.UpdatedAt, now)
+		d.documents[doc.ID] = doc
+	}
+	for _, a := range seed.Attachments {
+		if a.CreatedAt.IsZero() {
+			a.CreatedAt = now
+		}
+		d.attachments[a.ID] = a
+	}
+	for _, comment := range seed.Comments {
+		stamp(&comment.CreatedAt, &comment.UpdatedAt, now)
+		d.comments[comment.ID] = comment
+	}
+	return d, nil
+}
+
+// accountByEmail returns the id of the account with that login e-mail.
+// Callers hold d.mu.
+func (d *memoryData) accountByEmail(email string) (string, bool) {
+	email = strings.ToLower(email)
+	for id, account := range d.accounts {
+		if account.Email == email {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// stamp fills timestamps that the seed left out.
+func stamp(createdAt, updatedAt *time.Time, now time.Time) {
+	if createdAt.IsZero() {
+		*createdAt = now
+	}
+	if updatedAt.IsZero() {
+		*updatedAt = *createdAt
+	}
+}
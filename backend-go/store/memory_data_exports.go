@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"argumentum-backend/models"
+)
+
+type MemoryDataExportStore struct {
+	mu      sync.Mutex
+	exports map[string]models.DataExport
+}
+
+func NewMemoryDataExportStore() *MemoryDataExportStore {
+	return &MemoryDataExportStore{exports: make(map[string]models.DataExport)}
+}
+
+func (s *MemoryDataExportStore) Create(ctx context.Context, export *models.DataExport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exports[export.ID] = *export
+	return nil
+}
+
+func (s *MemoryDataExportStore) Get(ctx context.Context, id string) (*models.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	export, ok := s.exports[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &export, nil
+}
+
+func (s *MemoryDataExportStore) GetActive(ctx context.Context, userID string) (*models.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, export := range s.exports {
+		if export.UserID == userID &&
+			(export.Status == models.DataExportPending || export.Status == models.DataExportProcessing) {
+			return &export, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryDataExportStore) ListByUser(ctx context.Context, userID string) ([]models.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var exports []models.DataExport
+	for _, export := range s.exports {
+		if export.UserID == userID {
+			exports = append(exports, export)
+		}
+	}
+	return exports, nil
+}
+
+func (s *MemoryDataExportStore) ListExpired(ctx context.Context, t time.Time) ([]models.DataExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var expired []models.DataExport
+	for _, export := range s.exports {
+		if export.ExpiresAt.Before(t) {
+			expired = append(expired, export)
+		}
+	}
+	return expired, nil
+}
+
+func (s *MemoryDataExportStore) Update(ctx context.Context, export *models.DataExport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.exports[export.ID]; !ok {
+		return ErrNotFound
+	}
+	s.exports[export.ID] = *export
+	return nil
+}
+
+func (s *MemoryDataExportStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.exports, id)
+	return nil
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"argumentum-backend/models"
+)
+
+type MemoryDocumentStore struct {
+	data *memoryData
+}
+
+func (s *MemoryDocumentStore) ListDocuments(ctx context.Context, petitionIDs ...string) ([]models.PetitionDocument, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	wanted := idSet(petitionIDs)
+	var documents []models.PetitionDocument
+	for _, doc := range s.data.documents {
+		if wanted[doc.PetitionID] {
+			documents = append(documents, doc)
+		}
+	}
+	return documents, nil
+}
+
+func (s *MemoryDocumentStore) ListAttachments(ctx context.Context, petitionIDs ...string) ([]models.PetitionAttachment, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	wanted := idSet(petitionIDs)
+	var attachments []models.PetitionAttachment
+	for _, a := range s.data.attachments {
+		if wanted[a.PetitionID] {
+			attachments = append(attachments, a)
+		}
+	}
+	return attachments, nil
+}
+
+func (s *MemoryDocumentStore) ListComments(ctx context.Context, petitionIDs ...string) ([]models.PetitionComment, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	wanted := idSet(petitionIDs)
+	var comments []models.PetitionComment
+	for _, comment := range s.data.comments {
+		if wanted[comment.PetitionID] {
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].CreatedAt.Before(comments[j].CreatedAt) })
+	return comments, nil
+}
+
+func idSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"argumentum-backend/models"
+)
+
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]models.IdempotentRequest
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: make(map[string]models.IdempotentRequest)}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, req *models.IdempotentRequest) (*models.IdempotentRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.entries[req.Key]; ok && time.Now().Before(existing.ExpiresAt) {
+		return &existing, nil
+	}
+	s.entries[req.Key] = *req
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, key string, status int, contentType, body string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok {
+		return ErrNotFound
+	}
+	entry.Status, entry.ContentType, entry.Body = status, contentType, body
+	s.entries[key] = entry
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) TakeOver(ctx context.Context, req *models.IdempotentRequest, staleBefore time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.entries[req.Key]
+	if !ok || existing.Status != 0 || !existing.CreatedAt.Before(staleBefore) {
+		return false, nil
+	}
+	s.entries[req.Key] = *req
+	return true, nil
+}
+
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) DeleteExpired(ctx context.Context, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, entry := range s.entries {
+		if entry.ExpiresAt.Before(t) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"argumentum-backend/models"
+	"argumentum-backend/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const memoryRecoveryTTL = time.Hour
+
+// MemoryIdentityStore keeps credentials in memory. There is no e-mail: the
+// recovery code and link hash are written to the log instead.
+type MemoryIdentityStore struct {
+	data *memoryData
+}
+
+type memoryRecovery struct {
+	UserID    string
+	Code      string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+func (s *MemoryIdentityStore) SignIn(ctx context.Context, email, password string) (string, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	id, ok := s.data.accountByEmail(email)
+	if !ok {
+		return "", ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword(s.data.accounts[id].PasswordHash, []byte(password)) != nil {
+		return "", ErrInvalidCredentials
+	}
+	return id, nil
+}
+
+func (s *MemoryIdentityStore) SignUp(ctx context.Context, email, password string, metadata map[string]interface{}) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	if _, exists := s.data.accountByEmail(email); exists {
+		// Mesmo status e código que o GoTrue devolve
+		return "", &models.ApiError{
+			Status:  http.StatusUnprocessableEntity,
+			Code:    "user_already_exists",
+			Message: "User already registered",
+		}
+	}
+
+	id := utils.GenerateID()
+	now := time.Now().UTC()
+	s.data.accounts[id] = memoryAccount{Email: strings.ToLower(email), PasswordHash: hash}
+
+	// Faz o papel do trigger que cria o perfil no Postgres
+	profile := models.Profile{ID: id, Email: email, CreatedAt: now, UpdatedAt: now}
+	profile.Name, _ = metadata["full_name"].(string)
+	profile.TermsAccepted, _ = metadata["terms_accepted"].(bool)
+	if at, ok := metadata["terms_accepted_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, at); err == nil {
+			profile.TermsAcceptedAt = &t
+		}
+	}
+	s.data.profiles[id] = profile
+	return id, nil
+}
+
+func (s *MemoryIdentityStore) SendRecovery(ctx context.Context, email string) error {
+	tokenHash, err := utils.GenerateOpaqueToken(16)
+	if err != nil {
+		return err
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	id, ok := s.data.accountByEmail(email)
+	if !ok {
+		return nil
+	}
+	s.data.recoveries[id] = memoryRecovery{
+		UserID:    id,
+		Code:      code,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(memoryRecoveryTTL),
+	}
+	slog.InfoContext(ctx, "[memória] Código de recuperação de senha", "user_id", id, "recovery_code", code)
+	return nil
+}
+
+func (s *MemoryIdentityStore) VerifyRecovery(ctx context.Context, email, token string) (string, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	var recovery memoryRecovery
+	found := false
+	if email != "" {
+		if id, ok := s.data.accountByEmail(email); ok {
+			recovery, found = s.data.recoveries[id]
+			found = found && subtle.ConstantTimeCompare([]byte(recovery.Code), []byte(token)) == 1
+		}
+	} else {
+		for _, r := range s.data.recoveries {
+			if subtle.ConstantTimeCompare([]byte(r.TokenHash), []byte(token)) == 1 {
+				recovery, found = r, true
+				break
+			}
+		}
+	}
+	if !found || time.Now().After(recovery.ExpiresAt) {
+		return "", &models.ApiError{
+			Status:  http.StatusForbidden,
+			Code:    "otp_expired",
+			Message: "Token has expired or is invalid",
+		}
+	}
+
+	delete(s.data.recoveries, recovery.UserID)
+	return recovery.UserID, nil
+}
+
+func (s *MemoryIdentityStore) SetPassword(ctx context.Context, userID, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	account, ok := s.data.accounts[userID]
+	if !ok {
+		return ErrNotFound
+	}
+	account.PasswordHash = hash
+	s.data.accounts[userID] = account
+	return nil
+}
+
+func (s *MemoryIdentityStore) GetEmail(ctx context.Context, userID string) (string, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	account, ok := s.data.accounts[userID]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return account.Email, nil
+}
+
+func (s *MemoryIdentityStore) DeleteUser(ctx context.Context, userID string) error {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	if _, ok := s.data.accounts[userID]; !ok {
+		return ErrNotFound
+	}
+	delete(s.data.accounts, userID)
+	delete(s.data.profiles, userID)
+	return nil
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"argumentum-backend/models"
+)
+
+type MemoryMFAStore struct {
+	mu      sync.Mutex
+	configs map[string]models.MFAConfig
+}
+
+func NewMemoryMFAStore() *MemoryMFAStore {
+	return &MemoryMFAStore{configs: make(map[string]models.MFAConfig)}
+}
+
+func (s *MemoryMFAStore) Get(ctx context.Context, userID string) (*models.MFAConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	config, ok := s.configs[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	config.RecoveryCodeHashes = append([]string(nil), config.RecoveryCodeHashes...)
+	return &config, nil
+}
+
+func (s *MemoryMFAStore) Save(ctx context.Context, config *models.MFAConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved := *config
+	saved.RecoveryCodeHashes = append([]string(nil), config.RecoveryCodeHashes...)
+	s.configs[config.UserID] = saved
+	return nil
+}
+
+func (s *MemoryMFAStore) Update(ctx context.Context, config, prev *models.MFAConfig) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.configs[config.UserID]
+	if !ok || !sameMFAState(&current, prev) {
+		return false, nil
+	}
+	saved := *config
+	saved.RecoveryCodeHashes = append([]string(nil), config.RecoveryCodeHashes...)
+	s.configs[config.UserID] = saved
+	return true, nil
+}
+
+func sameMFAState(a, b *models.MFAConfig) bool {
+	if a.SecretEncrypted != b.SecretEncrypted || a.Enabled != b.Enabled || a.LastUsedStep != b.LastUsedStep ||
+		len(a.RecoveryCodeHashes) != len(b.RecoveryCodeHashes) {
+		return false
+	}
+	for i := range a.RecoveryCodeHashes {
+		if a.RecoveryCodeHashes[i] != b.RecoveryCodeHashes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *MemoryMFAStore) Delete(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.configs, userID)
+	return nil
+}
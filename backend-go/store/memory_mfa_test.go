@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"argumentum-backend/models"
+)
+
+// TestMemoryMFAUpdateRejectsReplay makes concurrent requests consume the same
+// TOTP step: only one of them may save it.
+func TestMemoryMFAUpdateRejectsReplay(t *testing.T) {
+	s := NewMemoryMFAStore()
+	ctx := context.Background()
+	prev := models.MFAConfig{UserID: "u1", SecretEncrypted: "s", Enabled: true, LastUsedStep: 10, RecoveryCodeHashes: []string{"a", "b"}}
+	if err := s.Save(ctx, &prev); err != nil {
+		t.Fatal(err)
+	}
+
+	var wins int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			next := prev
+			next.LastUsedStep = 11
+			if ok, err := s.Update(ctx, &next, &prev); err == nil && ok {
+				atomic.AddInt32(&wins, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if wins != 1 {
+		t.Fatalf("%d requests saved the same step, want 1", wins)
+	}
+
+	// The recovery code list is part of the state, too
+	stale := prev
+	stale.LastUsedStep = 11
+	next := stale
+	next.RecoveryCodeHashes = []string{"b"}
+	stale.RecoveryCodeHashes = []string{"a", "b", "c"}
+	if ok, _ := s.Update(ctx, &next, &stale); ok {
+		t.Fatal("update against stale recovery codes succeeded")
+	}
+}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"sort"
+
+	"argumentum-backend/models"
+)
+
+type MemoryPetitionStore struct {
+	data *memoryData
+}
+
+func (s *MemoryPetitionStore) Get(ctx context.Context, id string) (*models.Petition, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	petition, ok := s.data.petitions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	petition = copyPetition(petition)
+	return &petition, nil
+}
+
+func (s *MemoryPetitionStore) ListByUser(ctx context.Context, userID string) ([]models.Petition, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	var petitions []models.Petition
+	for _, p := range s.data.petitions {
+		if p.UserID == userID {
+			petitions = append(petitions, copyPetition(p))
+		}
+	}
+	sort.Slice(petitions, func(i, j int) bool {
+		return petitions[i].CreatedAt.After(petitions[j].CreatedAt)
+	})
+	return petitions, nil
+}
+
+func (s *MemoryPetitionStore) List(ctx context.Context, filter PetitionFilter) ([]models.Petition, int, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	teams := make(map[string]bool, len(filter.TeamIDs))
+	for _, id := range filter.TeamIDs {
+		teams[id] = true
+	}
+	var petitions []models.Petition
+	for _, p := range s.data.petitions {
+		visible := filter.All || (filter.UserID != "" && p.UserID == filter.UserID) || (p.TeamID != nil && teams[*p.TeamID])
+		if visible && (filter.Status == "" || p.Status == filter.Status) {
+			petitions = append(petitions, p)
+		}
+	}
+	sort.Slice(petitions, func(i, j int) bool {
+		if filter.Ascending {
+			return petitions[i].CreatedAt.Before(petitions[j].CreatedAt)
+		}
+		return petitions[i].CreatedAt.After(petitions[j].CreatedAt)
+	})
+
+	total := len(petitions)
+	start := filter.Offset
+	if start > total {
+		start = total
+	}
+	end := total
+	if filter.Limit > 0 && start+filter.Limit < end {
+		end = start + filter.Limit
+	}
+	page := make([]models.Petition, 0, end-start)
+	for _, p := range petitions[start:end] {
+		page = append(page, copyPetition(p))
+	}
+	return page, total, nil
+}
+
+func (s *MemoryPetitionStore) Create(ctx context.Context, petition *models.Petition, payerID string, cost int) error {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	if s.data.tokens[payerID] < cost {
+		return ErrInsufficientTokens
+	}
+	s.data.tokens[payerID] -= cost
+	s.data.petitions[petition.ID] = copyPetition(*petition)
+	return nil
+}
+
+func (s *MemoryPetitionStore) Update(ctx context.Context, petition *models.Petition) error {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	current, ok := s.data.petitions[petition.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	updated := copyPetition(*petition)
+	// Dono e data de criação não mudam, como no Supabase
+	updated.UserID = current.UserID
+	updated.CreatedAt = current.CreatedAt
+	s.data.petitions[petition.ID] = updated
+	return nil
+}
+
+func (s *MemoryPetitionStore) Delete(ctx context.Context, id string) error {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	delete(s.data.petitions, id)
+	for docID, doc := range s.data.documents {
+		if doc.PetitionID == id {
+			delete(s.data.documents, docID)
+		}
+	}
+	for attachmentID, attachment := range s.data.attachments {
+		if attachment.PetitionID == id {
+			delete(s.data.attachments, attachmentID)
+		}
+	}
+	return nil
+}
+
+func copyPetition(p models.Petition) models.Petition {
+	p.FormAnswers = append([]byte(nil), p.FormAnswers...)
+	return p
+}
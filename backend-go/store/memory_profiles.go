@@ -0,0 +1,21 @@
+package store
+
+import (
+	"context"
+
+	"argumentum-backend/models"
+)
+
+type MemoryProfileStore struct {
+	data *memoryData
+}
+
+func (s *MemoryProfileStore) Get(ctx context.Context, id string) (*models.Profile, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	profile, ok := s.data.profiles[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &profile, nil
+}
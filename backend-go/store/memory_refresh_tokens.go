@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"argumentum-backend/models"
+)
+
+type MemoryRefreshTokenStore struct {
+	mu       sync.Mutex
+	sessions map[string]models.Session
+	tokens   map[string]models.RefreshToken
+	byHash   map[string]string
+}
+
+func NewMemoryRefreshTokenStore() *MemoryRefreshTokenStore {
+	return &MemoryRefreshTokenStore{
+		sessions: make(map[string]models.Session),
+		tokens:   make(map[string]models.RefreshToken),
+		byHash:   make(map[string]string),
+	}
+}
+
+func (s *MemoryRefreshTokenStore) CreateSession(ctx context.Context, session *models.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &session, nil
+}
+
+func (s *MemoryRefreshTokenStore) ListUserSessions(ctx context.Context, userID string) ([]models.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var sessions []models.Session
+	for _, session := range s.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && now.Before(session.ExpiresAt) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt) })
+	return sessions, nil
+}
+
+func (s *MemoryRefreshTokenStore) TouchSession(ctx context.Context, id string, at time.Time, ipAddress, userAgent string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session, ok := s.sessions[id]; ok {
+		session.LastSeenAt = at
+		session.IPAddress = ipAddress
+		session.UserAgent = userAgent
+		s.sessions[id] = session
+	}
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) RevokeSession(ctx context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if ok && session.RevokedAt == nil {
+		session.RevokedAt = &at
+		s.sessions[id] = session
+	}
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) RevokeUserSessions(ctx context.Context, userID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, session := range s.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &at
+			s.sessions[id] = session
+		}
+	}
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) CreateToken(ctx context.Context, token *models.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token.ID] = *token
+	s.byHash[token.TokenHash] = token.ID
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) GetTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.byHash[hash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	token := s.tokens[id]
+	return &token, nil
+}
+
+func (s *MemoryRefreshTokenStore) MarkTokenUsed(ctx context.Context, id string, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[id]
+	if !ok {
+		return false, ErrNotFound
+	}
+	if token.UsedAt != nil {
+		// Same call repeated: not a replay
+		return token.UsedAt.Equal(at), nil
+	}
+	token.UsedAt = &at
+	s.tokens[id] = token
+	return true, nil
+}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"argumentum-backend/models"
+)
+
+func TestMemoryMarkTokenUsed(t *testing.T) {
+	s := NewMemoryRefreshTokenStore()
+	ctx := context.Background()
+	if err := s.CreateToken(ctx, &models.RefreshToken{ID: "t1", TokenHash: "h1"}); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now()
+
+	for _, tc := range []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"first use", at, true},
+		{"same call retried", at, true},
+		{"replay", at.Add(time.Second), false},
+	} {
+		if fresh, err := s.MarkTokenUsed(ctx, "t1", tc.at); err != nil || fresh != tc.want {
+			t.Errorf("%s: MarkTokenUsed = %v, %v; want %v", tc.name, fresh, err, tc.want)
+		}
+	}
+	if _, err := s.MarkTokenUsed(ctx, "missing", at); err != ErrNotFound {
+		t.Errorf("unknown token: err = %v, want ErrNotFound", err)
+	}
+}
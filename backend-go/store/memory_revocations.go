@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"argumentum-backend/utils"
+)
+
+type revocation struct {
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+type MemoryRevocationList struct {
+	mu      sync.Mutex
+	entries map[string]revocation
+}
+
+func NewMemoryRevocationList() *MemoryRevocationList {
+	return &MemoryRevocationList{entries: make(map[string]revocation)}
+}
+
+func (l *MemoryRevocationList) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	l.put(tokenRevocationKey(jti), time.Now(), expiresAt)
+	return nil
+}
+
+func (l *MemoryRevocationList) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	l.put(sessionRevocationKey(sessionID), time.Now(), expiresAt)
+	return nil
+}
+
+func (l *MemoryRevocationList) RevokeUserTokens(ctx context.Context, userID string, cutoff time.Time) error {
+	l.put(userRevocationKey(userID), cutoff, cutoff.Add(utils.AccessTokenTTL))
+	return nil
+}
+
+func (l *MemoryRevocationList) IsRevoked(ctx context.Context, jti, sessionID, userID string, issuedAt time.Time) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if e, ok := l.entries[tokenRevocationKey(jti)]; ok && now.Before(e.expiresAt) {
+		return true, nil
+	}
+	if sessionID != "" {
+		if e, ok := l.entries[sessionRevocationKey(sessionID)]; ok && now.Before(e.expiresAt) {
+			return true, nil
+		}
+	}
+	if e, ok := l.entries[userRevocationKey(userID)]; ok && now.Before(e.expiresAt) && !issuedAt.After(e.revokedAt) {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (l *MemoryRevocationList) put(key string, revokedAt, expiresAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for k, e := range l.entries {
+		if now.After(e.expiresAt) {
+			delete(l.entries, k)
+		}
+	}
+	l.entries[key] = revocation{revokedAt: revokedAt.Truncate(time.Millisecond), expiresAt: expiresAt}
+}
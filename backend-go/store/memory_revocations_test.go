@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"argumentum-backend/utils"
+)
+
+func TestMemoryRevokeUserTokensIncludesCutoff(t *testing.T) {
+	l := NewMemoryRevocationList()
+	ctx := context.Background()
+	cutoff := time.Now().Truncate(time.Millisecond)
+	if err := l.RevokeUserTokens(ctx, "u1", cutoff); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		userID   string
+		issuedAt time.Time
+		want     bool
+	}{
+		{"issued before", "u1", cutoff.Add(-time.Second), true},
+		{"issued at the cutoff", "u1", cutoff, true},
+		{"issued after", "u1", cutoff.Add(time.Millisecond), false},
+		{"other user", "u2", cutoff, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			revoked, err := l.IsRevoked(ctx, "jti", "", tc.userID, tc.issuedAt)
+			if err != nil || revoked != tc.want {
+				t.Fatalf("IsRevoked = %v, %v; want %v", revoked, err, tc.want)
+			}
+		})
+	}
+}
+
+// A token issued right after the cutoff keeps working, even within the same
+// millisecond: IssuedAfter moves its iat past the cutoff.
+func TestMemoryRevokeUserTokensSparesNewToken(t *testing.T) {
+	l := NewMemoryRevocationList()
+	ctx := context.Background()
+	cutoff := time.Now()
+	if err := l.RevokeUserTokens(ctx, "u1", cutoff); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := utils.GenerateJWT(utils.Identity{UserID: "u1", SessionID: "s2", IssuedAfter: cutoff})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := utils.ValidateJWT(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := l.IsRevoked(ctx, claims.ID, claims.SessionID, claims.UserID, claims.IssuedAt.Time)
+	if err != nil || revoked {
+		t.Fatalf("token issued after the cutoff: revoked=%v, err=%v", revoked, err)
+	}
+}
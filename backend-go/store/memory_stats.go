@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+
+	"argumentum-backend/models"
+)
+
+type MemoryStatsStore struct {
+	data *memoryData
+}
+
+// Admin counts like get_admin_stats.
+func (s *MemoryStatsStore) Admin(ctx context.Context) (*models.AdminStats, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	stats := &models.AdminStats{DistributionByStatus: map[string]int{}}
+	for _, p := range s.data.petitions {
+		stats.TotalPetitions++
+		stats.DistributionByStatus[p.Status]++
+		if p.Status == models.PetitionStatusPending {
+			stats.PendingPetitions++
+		}
+	}
+	for _, p := range s.data.profiles {
+		if !p.IsAdmin {
+			stats.TotalUsers++
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"argumentum-backend/models"
+)
+
+func TestMemoryAdminStats(t *testing.T) {
+	repos, err := NewMemoryRepositories(&Seed{
+		Users: []SeedUser{
+			{Profile: models.Profile{ID: "admin", Email: "admin@x.test", IsAdmin: true}},
+			{Profile: models.Profile{ID: "u1", Email: "u1@x.test"}},
+			{Profile: models.Profile{ID: "u2", Email: "u2@x.test"}},
+		},
+		Petitions: []models.Petition{
+			{ID: "p1", UserID: "u1", Status: models.PetitionStatusPending},
+			{ID: "p2", UserID: "u1", Status: models.PetitionStatusPending},
+			{ID: "p3", UserID: "u2", Status: "approved"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := repos.Stats.Admin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TotalPetitions != 3 || stats.TotalUsers != 2 || stats.PendingPetitions != 2 {
+		t.Fatalf("stats = %+v, want 3 petitions, 2 users, 2 pending", stats)
+	}
+	if stats.DistributionByStatus["pending"] != 2 || stats.DistributionByStatus["approved"] != 1 {
+		t.Fatalf("distribution = %v", stats.DistributionByStatus)
+	}
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"argumentum-backend/models"
+	"argumentum-backend/utils"
+)
+
+type MemoryTeamStore struct {
+	data *memoryData
+}
+
+func (s *MemoryTeamStore) Get(ctx context.Context, id string) (*models.Team, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	team, ok := s.data.teams[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &team, nil
+}
+
+func (s *MemoryTeamStore) Create(ctx context.Context, team *models.Team) error {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	s.data.teams[team.ID] = *team
+	return nil
+}
+
+func (s *MemoryTeamStore) Delete(ctx context.Context, ids ...string) error {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	for _, id := range ids {
+		delete(s.data.teams, id)
+		for memberID, m := range s.data.members {
+			if m.TeamID == id {
+				delete(s.data.members, memberID)
+			}
+		}
+		for inviteID, i := range s.data.invites {
+			if i.TeamID == id {
+				delete(s.data.invites, inviteID)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *MemoryTeamStore) GetInvite(ctx context.Context, id string) (*models.TeamInvite, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	invite, ok := s.data.invites[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &invite, nil
+}
+
+// AcceptInvite mirrors the accept_team_invite function.
+func (s *MemoryTeamStore) AcceptInvite(ctx context.Context, inviteID, userID, email string) error {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	invite, ok := s.data.invites[inviteID]
+	switch {
+	case !ok:
+		return &InviteRejectedError{Code: "invite_not_found"}
+	case invite.Status != models.InviteStatusPending:
+		return &InviteRejectedError{Code: "invite_not_pending"}
+	case invite.ExpiresAt.Before(time.Now()):
+		return &InviteRejectedError{Code: "invite_expired"}
+	case !strings.EqualFold(invite.Email, email):
+		return &InviteRejectedError{Code: "invite_email_mismatch"}
+	}
+
+	isMember := false
+	for _, m := range s.data.members {
+		if m.TeamID == invite.TeamID && m.UserID == userID {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		role := invite.Role
+		if role == "" {
+			role = models.TeamRoleOperador
+		}
+		now := time.Now().UTC()
+		member := models.TeamMember{
+			ID:        utils.GenerateID(),
+			TeamID:    invite.TeamID,
+			UserID:    userID,
+			Role:      role,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		s.data.members[member.ID] = member
+	}
+
+	invite.Status = models.InviteStatusAccepted
+	s.data.invites[inviteID] = invite
+	return nil
+}
+
+type MemoryTeamMemberStore struct {
+	data *memoryData
+}
+
+func (s *MemoryTeamMemberStore) ListByUser(ctx context.Context, userID string) ([]models.TeamMember, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	var members []models.TeamMember
+	for _, m := range s.data.members {
+		if m.UserID == userID {
+			members = append(members, m)
+		}
+	}
+	return members, nil
+}
+
+func (s *MemoryTeamMemberStore) ListByTeams(ctx context.Context, teamIDs ...string) ([]models.TeamMember, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	wanted := idSet(teamIDs)
+	var members []models.TeamMember
+	for _, m := range s.data.members {
+		if wanted[m.TeamID] {
+			members = append(members, m)
+		}
+	}
+	return members, nil
+}
+
+func (s *MemoryTeamMemberStore) Add(ctx context.Context, member *models.TeamMember) error {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	s.data.members[member.ID] = *member
+	return nil
+}
@@ -0,0 +1,13 @@
+package store
+
+import "context"
+
+type MemoryTokenStore struct {
+	data *memoryData
+}
+
+func (s *MemoryTokenStore) Balance(ctx context.Context, userID string) (int, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+	return s.data.tokens[userID], nil
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+// errNoMemoryStorage is returned for files: the in-memory dataset keeps
+// their metadata only.
+var errNoMemoryStorage = errors.New("storage objects are not kept in memory")
+
+// MemoryUserDataStore reads the tables kept by the in-memory repositories.
+// Tables they do not keep (petition_settings, token_transactions) have no
+// rows.
+type MemoryUserDataStore struct {
+	data *memoryData
+}
+
+func (s *MemoryUserDataStore) Rows(ctx context.Context, table, column string, values ...string) ([]json.RawMessage, error) {
+	s.data.mu.Lock()
+	var all []interface{}
+	switch table {
+	case "profiles":
+		for _, r := range s.data.profiles {
+			all = append(all, r)
+		}
+	case "petitions":
+		for _, r := range s.data.petitions {
+			all = append(all, r)
+		}
+	case "petition_comments":
+		for _, r := range s.data.comments {
+			all = append(all, r)
+		}
+	case "petition_documents":
+		for _, r := range s.data.documents {
+			all = append(all, r)
+		}
+	case "petition_attachments":
+		for _, r := range s.data.attachments {
+			all = append(all, r)
+		}
+	case "team_members":
+		for _, r := range s.data.members {
+			all = append(all, r)
+		}
+	}
+	s.data.mu.Unlock()
+
+	// Filtra pela coluna como o Postgres, usando os nomes JSON dos modelos
+	wanted := idSet(values)
+	var rows []json.RawMessage
+	var ids []string
+	for _, r := range all {
+		raw, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+		if value, ok := fields[column].(string); ok && wanted[value] {
+			rows = append(rows, raw)
+			id, _ := fields["id"].(string)
+			ids = append(ids, id)
+		}
+	}
+	sort.Sort(rowsByID{rows, ids})
+	return rows, nil
+}
+
+// rowsByID orders rows like the Supabase store, by id.
+type rowsByID struct {
+	rows []json.RawMessage
+	ids  []string
+}
+
+func (r rowsByID) Len() int           { return len(r.rows) }
+func (r rowsByID) Less(i, j int) bool { return r.ids[i] < r.ids[j] }
+func (r rowsByID) Swap(i, j int) {
+	r.rows[i], r.rows[j] = r.rows[j], r.rows[i]
+	r.ids[i], r.ids[j] = r.ids[j], r.ids[i]
+}
+
+func (s *MemoryUserDataStore) StorageObject(ctx context.Context, bucket, path string) (io.ReadCloser, error) {
+	return nil, errNoMemoryStorage
+}
+
+// SettingsStorageKeys returns nothing: petition settings are not kept in
+// memory.
+func (s *MemoryUserDataStore) SettingsStorageKeys(ctx context.Context, userID string) ([]string, error) {
+	return nil, nil
+}
+
+// Erase removes the same rows as delete_user_data.
+func (s *MemoryUserDataStore) Erase(ctx context.Context, userID string) error {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	petitions := make(map[string]bool)
+	for id, p := range s.data.petitions {
+		if p.UserID == userID {
+			petitions[id] = true
+			delete(s.data.petitions, id)
+		}
+	}
+	for id, d := range s.data.documents {
+		if petitions[d.PetitionID] {
+			delete(s.data.documents, id)
+		}
+	}
+	for id, a := range s.data.attachments {
+		if petitions[a.PetitionID] {
+			delete(s.data.attachments, id)
+		}
+	}
+	for id, c := range s.data.comments {
+		if petitions[c.PetitionID] {
+			delete(s.data.comments, id)
+		}
+	}
+	for id, m := range s.data.members {
+		if m.UserID == userID {
+			delete(s.data.members, id)
+		}
+	}
+	if account, ok := s.data.accounts[userID]; ok {
+		for id, i := range s.data.invites {
+			if strings.EqualFold(i.Email, account.Email) {
+				delete(s.data.invites, id)
+			}
+		}
+	}
+	for id, r := range s.data.recoveries {
+		if r.UserID == userID {
+			delete(s.data.recoveries, id)
+		}
+	}
+	delete(s.data.tokens, userID)
+	delete(s.data.profiles, userID)
+	delete(s.data.accounts, userID)
+	return nil
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// MFAStore persists TOTP enrollments, one per user.
+type MFAStore interface {
+	Get(ctx context.Context, userID string) (*models.MFAConfig, error)
+	// Save creates or replaces the user's enrollment.
+	Save(ctx context.Context, config *models.MFAConfig) error
+	// Update replaces the enrollment only if the stored one still equals prev
+	// (same secret, state, last used step and recovery codes). It reports
+	// false when another request changed it first, e.g. by using the same
+	// code.
+	Update(ctx context.Context, config, prev *models.MFAConfig) (bool, error)
+	Delete(ctx context.Context, userID string) error
+}
+
+func NewMFAStore(db *gateway.Client, memory bool) MFAStore {
+	if memory {
+		slog.Warn("Usando armazenamento de 2FA em memória")
+		return NewMemoryMFAStore()
+	}
+	return NewSupabaseMFAStore(db)
+}
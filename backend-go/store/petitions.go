@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"argumentum-backend/models"
+)
+
+// ErrInsufficientTokens is returned by PetitionStore.Create when the payer's
+// balance does not cover the cost.
+var ErrInsufficientTokens = errors.New("insufficient tokens")
+
+// PetitionFilter selects the petitions returned by PetitionStore.List.
+type PetitionFilter struct {
+	// UserID and TeamIDs match the petitions the user owns or that belong to
+	// one of the teams. All matches every petition, for administrators.
+	UserID  string
+	TeamIDs []string
+	All     bool
+	// Status, when set, keeps only petitions with that status.
+	Status string
+	// Ascending lists the oldest first instead of the newest.
+	Ascending bool
+	Limit     int
+	Offset    int
+}
+
+// PetitionStore persists petitions.
+type PetitionStore interface {
+	Get(ctx context.Context, id string) (*models.Petition, error)
+	// ListByUser returns the user's petitions, newest first.
+	ListByUser(ctx context.Context, userID string) ([]models.Petition, error)
+	// List returns a page of the petitions matching filter and how many
+	// match in total.
+	List(ctx context.Context, filter PetitionFilter) ([]models.Petition, int, error)
+	// Create saves the petition and debits cost tokens from payerID in the
+	// same transaction, as a petition_creation token transaction. It fails
+	// with ErrInsufficientTokens when the balance is lower than cost.
+	Create(ctx context.Context, petition *models.Petition, payerID string, cost int) error
+	Update(ctx context.Context, petition *models.Petition) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,13 @@
+package store
+
+import (
+	"context"
+
+	"argumentum-backend/models"
+)
+
+// ProfileStore reads public.profiles. Profiles are created together with the
+// account by IdentityStore.SignUp.
+type ProfileStore interface {
+	Get(ctx context.Context, id string) (*models.Profile, error)
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// RefreshTokenStore persists sessions (token families) and their refresh tokens.
+type RefreshTokenStore interface {
+	CreateSession(ctx context.Context, session *models.Session) error
+	GetSession(ctx context.Context, id string) (*models.Session, error)
+	// ListUserSessions returns the user's sessions that are neither revoked nor
+	// expired, most recently used first.
+	ListUserSessions(ctx context.Context, userID string) ([]models.Session, error)
+	// TouchSession records activity on the session from the given client.
+	TouchSession(ctx context.Context, id string, at time.Time, ipAddress, userAgent string) error
+	// RevokeSession revokes the session and, with it, every refresh token of the family.
+	RevokeSession(ctx context.Context, id string, at time.Time) error
+	// RevokeUserSessions revokes every active session of the user.
+	RevokeUserSessions(ctx context.Context, userID string, at time.Time) error
+
+	CreateToken(ctx context.Context, token *models.RefreshToken) error
+	GetTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	// MarkTokenUsed flags the token as consumed. It returns false when the token
+	// had already been used, which callers must treat as a replay. Repeating
+	// the call with the same at returns true, so a retried request is not
+	// mistaken for one.
+	MarkTokenUsed(ctx context.Context, id string, at time.Time) (bool, error)
+}
+
+// NewRefreshTokenStore returns the in-memory store when memory is set
+// (AUTH_STORE=memory) and the Supabase one otherwise.
+func NewRefreshTokenStore(db *gateway.Client, memory bool) RefreshTokenStore {
+	if memory {
+		slog.Warn("Usando armazenamento de sessões em memória")
+		return NewMemoryRefreshTokenStore()
+	}
+	return NewSupabaseRefreshTokenStore(db)
+}
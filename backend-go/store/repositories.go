@@ -0,0 +1,71 @@
+package store
+
+import (
+	"log/slog"
+
+	"argumentum-backend/internal/gateway"
+)
+
+// Repositories groups the application data stores handed to the handlers.
+type Repositories struct {
+	Identity    IdentityStore
+	Profiles    ProfileStore
+	Petitions   PetitionStore
+	Teams       TeamStore
+	TeamMembers TeamMemberStore
+	Tokens      TokenStore
+	Documents   DocumentStore
+	UserData    UserDataStore
+	Stats       StatsStore
+}
+
+// NewRepositories returns the Supabase repositories unless memory is set
+// (DATA_STORE=memory). The in-memory repositories start from the seed in
+// seedFile, or the bundled development seed, so the API runs without a
+// Supabase project.
+func NewRepositories(db *gateway.Client, memory bool, seedFile string) (*Repositories, error) {
+	if !memory {
+		return NewSupabaseRepositories(db), nil
+	}
+
+	seed, err := LoadSeed(seedFile)
+	if err != nil {
+		return nil, err
+	}
+	slog.Warn("Usando repositórios em memória", "seed_users", len(seed.Users))
+	return NewMemoryRepositories(seed)
+}
+
+func NewSupabaseRepositories(db *gateway.Client) *Repositories {
+	return &Repositories{
+		Identity:    NewSupabaseIdentityStore(db),
+		Profiles:    NewSupabaseProfileStore(db),
+		Petitions:   NewSupabasePetitionStore(db),
+		Teams:       NewSupabaseTeamStore(db),
+		TeamMembers: NewSupabaseTeamMemberStore(db),
+		Tokens:      NewSupabaseTokenStore(db),
+		Documents:   NewSupabaseDocumentStore(db),
+		UserData:    NewSupabaseUserDataStore(db),
+		Stats:       NewSupabaseStatsStore(db),
+	}
+}
+
+// NewMemoryRepositories returns repositories sharing one in-memory dataset,
+// filled from seed when it is not nil.
+func NewMemoryRepositories(seed *Seed) (*Repositories, error) {
+	data, err := newMemoryData(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &Repositories{
+		Identity:    &MemoryIdentityStore{data: data},
+		Profiles:    &MemoryProfileStore{data: data},
+		Petitions:   &MemoryPetitionStore{data: data},
+		Teams:       &MemoryTeamStore{data: data},
+		TeamMembers: &MemoryTeamMemberStore{data: data},
+		Tokens:      &MemoryTokenStore{data: data},
+		Documents:   &MemoryDocumentStore{data: data},
+		UserData:    &MemoryUserDataStore{data: data},
+		Stats:       &MemoryStatsStore{data: data},
+	}, nil
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+)
+
+// RevocationList records access tokens that must be rejected before they
+// expire. Entries only need to live as long as the tokens they cover.
+type RevocationList interface {
+	// RevokeToken rejects a single access token by its jti.
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeSession rejects every access token carrying the given sid.
+	RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error
+	// RevokeUserTokens rejects every access token of the user issued up to
+	// the cutoff, the cutoff instant included.
+	RevokeUserTokens(ctx context.Context, userID string, cutoff time.Time) error
+	IsRevoked(ctx context.Context, jti, sessionID, userID string, issuedAt time.Time) (bool, error)
+}
+
+func NewRevocationList(db *gateway.Client, memory bool) RevocationList {
+	if memory {
+		slog.Warn("Usando lista de revogação em memória")
+		return NewMemoryRevocationList()
+	}
+	return NewSupabaseRevocationList(db)
+}
+
+func tokenRevocationKey(jti string) string         { return "jti:" + jti }
+func sessionRevocationKey(sessionID string) string { return "sid:" + sessionID }
+func userRevocationKey(userID string) string       { return "user:" + userID }
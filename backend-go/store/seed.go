@@ -0,0 +1,50 @@
+package store
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+
+	"argumentum-backend/models"
+)
+
+//go:embed seed/dev.json
+var defaultSeed []byte
+
+// Seed is the initial content of the in-memory repositories. Rows use the
+// same JSON shape as the Supabase tables.
+type Seed struct {
+	Users       []SeedUser                  `json:"users"`
+	Teams       []models.Team               `json:"teams"`
+	TeamMembers []models.TeamMember         `json:"team_members"`
+	TeamInvites []models.TeamInvite         `json:"team_invites"`
+	Tokens      []models.UserTokens         `json:"user_tokens"`
+	Petitions   []models.Petition           `json:"petitions"`
+	Documents   []models.PetitionDocument   `json:"petition_documents"`
+	Attachments []models.PetitionAttachment `json:"petition_attachments"`
+	Comments    []models.PetitionComment    `json:"petition_comments"`
+}
+
+// SeedUser is a profile plus the password it logs in with.
+type SeedUser struct {
+	models.Profile
+	Password string `json:"password"`
+}
+
+// LoadSeed reads the seed file at path, or the bundled development seed when
+// path is empty.
+func LoadSeed(path string) (*Seed, error) {
+	data := defaultSeed
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var seed Seed
+	if err := json.Unmarshal(data, &seed); err != nil {
+		return nil, err
+	}
+	return &seed, nil
+}
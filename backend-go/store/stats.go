@@ -0,0 +1,12 @@
+package store
+
+import (
+	"context"
+
+	"argumentum-backend/models"
+)
+
+// StatsStore computes the platform figures shown to administrators.
+type StatsStore interface {
+	Admin(ctx context.Context) (*models.AdminStats, error)
+}
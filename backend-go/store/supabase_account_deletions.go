@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseAccountDeletionStore keeps requests in public.account_deletion_requests.
+type SupabaseAccountDeletionStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseAccountDeletionStore(db *gateway.Client) *SupabaseAccountDeletionStore {
+	return &SupabaseAccountDeletionStore{db: db}
+}
+
+func (s *SupabaseAccountDeletionStore) Create(ctx context.Context, deletion *models.AccountDeletion) error {
+	return s.db.Insert(ctx, "account_deletion_requests", deletion, nil)
+}
+
+func (s *SupabaseAccountDeletionStore) Get(ctx context.Context, id string) (*models.AccountDeletion, error) {
+	return s.getOne(ctx, gateway.NewQuery().Eq("id", id))
+}
+
+func (s *SupabaseAccountDeletionStore) GetPending(ctx context.Context, userID string) (*models.AccountDeletion, error) {
+	return s.getOne(ctx, gateway.NewQuery().Eq("user_id", userID).Eq("status", models.AccountDeletionPending))
+}
+
+func (s *SupabaseAccountDeletionStore) getOne(ctx context.Context, q *gateway.Query) (*models.AccountDeletion, error) {
+	var deletions []models.AccountDeletion
+	if err := s.db.Select(ctx, "account_deletion_requests", q, &deletions); err != nil {
+		return nil, err
+	}
+	if len(deletions) == 0 {
+		return nil, ErrNotFound
+	}
+	return &deletions[0], nil
+}
+
+func (s *SupabaseAccountDeletionStore) ListDue(ctx context.Context, t time.Time) ([]models.AccountDeletion, error) {
+	var deletions []models.AccountDeletion
+	q := gateway.NewQuery().
+		Eq("status", models.AccountDeletionPending).
+		Lt("scheduled_for", t.UTC().Format(time.RFC3339))
+	if err := s.db.Select(ctx, "account_deletion_requests", q, &deletions); err != nil {
+		return nil, err
+	}
+	return deletions, nil
+}
+
+// Claim is a conditional update: the filters on status and locked_until make
+// PostgREST touch the row only if no other worker holds the lease.
+func (s *SupabaseAccountDeletionStore) Claim(ctx context.Context, id string, now, until time.Time) (bool, error) {
+	q := gateway.NewQuery().
+		Eq("id", id).
+		Eq("status", models.AccountDeletionPending).
+		Or("locked_until.is.null", gateway.Cond("locked_until", "lt", now.UTC().Format(time.RFC3339)))
+	payload := map[string]interface{}{"locked_until": until.UTC()}
+	var updated []models.AccountDeletion
+	if err := s.db.Update(ctx, "account_deletion_requests", q, payload, &updated); err != nil {
+		return false, err
+	}
+	return len(updated) > 0, nil
+}
+
+// Cancel uses the same filters as Claim, so a request the worker is erasing
+// is left alone.
+func (s *SupabaseAccountDeletionStore) Cancel(ctx context.Context, id string, now time.Time) (bool, error) {
+	q := gateway.NewQuery().
+		Eq("id", id).
+		Eq("status", models.AccountDeletionPending).
+		Or("locked_until.is.null", gateway.Cond("locked_until", "lt", now.UTC().Format(time.RFC3339)))
+	payload := map[string]interface{}{
+		"status":       models.AccountDeletionCancelled,
+		"cancelled_at": now.UTC(),
+	}
+	var updated []models.AccountDeletion
+	if err := s.db.Update(ctx, "account_deletion_requests", q, payload, &updated); err != nil {
+		return false, err
+	}
+	return len(updated) > 0, nil
+}
+
+func (s *SupabaseAccountDeletionStore) Update(ctx context.Context, deletion *models.AccountDeletion) error {
+	payload := map[string]interface{}{
+		"status":       deletion.Status,
+		"cancelled_at": deletion.CancelledAt,
+		"completed_at": deletion.CompletedAt,
+		"receipt":      deletion.Receipt,
+		"last_error":   deletion.LastError,
+	}
+	var updated []models.AccountDeletion
+	if err := s.db.Update(ctx, "account_deletion_requests", gateway.NewQuery().Eq("id", deletion.ID), payload, &updated); err != nil {
+		return err
+	}
+	if len(updated) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseAPIKeyStore keeps API keys in public.api_keys.
+type SupabaseAPIKeyStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseAPIKeyStore(db *gateway.Client) *SupabaseAPIKeyStore {
+	return &SupabaseAPIKeyStore{db: db}
+}
+
+func (s *SupabaseAPIKeyStore) Create(ctx context.Context, key *models.APIKey) error {
+	return s.db.Insert(ctx, "api_keys", key, nil)
+}
+
+func (s *SupabaseAPIKeyStore) Get(ctx context.Context, id string) (*models.APIKey, error) {
+	return s.getOne(ctx, gateway.NewQuery().Eq("id", id))
+}
+
+func (s *SupabaseAPIKeyStore) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	return s.getOne(ctx, gateway.NewQuery().Eq("key_hash", hash))
+}
+
+func (s *SupabaseAPIKeyStore) getOne(ctx context.Context, q *gateway.Query) (*models.APIKey, error) {
+	var keys []models.APIKey
+	if err := s.db.Select(ctx, "api_keys", q, &keys); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrNotFound
+	}
+	return &keys[0], nil
+}
+
+func (s *SupabaseAPIKeyStore) ListByUser(ctx context.Context, userID string) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	q := gateway.NewQuery().Eq("user_id", userID).IsNull("revoked_at").Order("created_at", true)
+	if err := s.db.Select(ctx, "api_keys", q, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *SupabaseAPIKeyStore) Update(ctx context.Context, key *models.APIKey) error {
+	var updated []models.APIKey
+	payload := map[string]interface{}{"name": key.Name, "scopes": key.Scopes}
+	if err := s.db.Update(ctx, "api_keys", gateway.NewQuery().Eq("id", key.ID), payload, &updated); err != nil {
+		return err
+	}
+	if len(updated) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SupabaseAPIKeyStore) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	payload := map[string]interface{}{"last_used_at": at.UTC()}
+	return s.db.Update(ctx, "api_keys", gateway.NewQuery().Eq("id", id), payload, nil)
+}
+
+func (s *SupabaseAPIKeyStore) Revoke(ctx context.Context, id string, at time.Time) error {
+	q := gateway.NewQuery().Eq("id", id).IsNull("revoked_at")
+	payload := map[string]interface{}{"revoked_at": at.UTC()}
+	return s.db.Update(ctx, "api_keys", q, payload, nil)
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseDataExportStore keeps exports in public.data_exports.
+type SupabaseDataExportStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseDataExportStore(db *gateway.Client) *SupabaseDataExportStore {
+	return &SupabaseDataExportStore{db: db}
+}
+
+func (s *SupabaseDataExportStore) Create(ctx context.Context, export *models.DataExport) error {
+	return s.db.Insert(ctx, "data_exports", export, nil)
+}
+
+func (s *SupabaseDataExportStore) Get(ctx context.Context, id string) (*models.DataExport, error) {
+	return s.getOne(ctx, gateway.NewQuery().Eq("id", id))
+}
+
+func (s *SupabaseDataExportStore) GetActive(ctx context.Context, userID string) (*models.DataExport, error) {
+	q := gateway.NewQuery().
+		Eq("user_id", userID).
+		In("status", models.DataExportPending, models.DataExportProcessing).
+		Limit(1)
+	return s.getOne(ctx, q)
+}
+
+func (s *SupabaseDataExportStore) getOne(ctx context.Context, q *gateway.Query) (*models.DataExport, error) {
+	var exports []models.DataExport
+	if err := s.db.Select(ctx, "data_exports", q, &exports); err != nil {
+		return nil, err
+	}
+	if len(exports) == 0 {
+		return nil, ErrNotFound
+	}
+	return &exports[0], nil
+}
+
+func (s *SupabaseDataExportStore) ListByUser(ctx context.Context, userID string) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	if err := s.db.Select(ctx, "data_exports", gateway.NewQuery().Eq("user_id", userID), &exports); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (s *SupabaseDataExportStore) ListExpired(ctx context.Context, t time.Time) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	q := gateway.NewQuery().Lt("expires_at", t.UTC().Format(time.RFC3339))
+	if err := s.db.Select(ctx, "data_exports", q, &exports); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (s *SupabaseDataExportStore) Update(ctx context.Context, export *models.DataExport) error {
+	payload := map[string]interface{}{
+		"status":       export.Status,
+		"object_key":   export.ObjectKey,
+		"size_bytes":   export.SizeBytes,
+		"error":        export.Error,
+		"completed_at": export.CompletedAt,
+	}
+	return s.db.Update(ctx, "data_exports", gateway.NewQuery().Eq("id", export.ID), payload, nil)
+}
+
+func (s *SupabaseDataExportStore) Delete(ctx context.Context, id string) error {
+	return s.db.Delete(ctx, "data_exports", gateway.NewQuery().Eq("id", id))
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseDocumentStore reads public.petition_documents,
+// public.petition_attachments and public.petition_comments.
+type SupabaseDocumentStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseDocumentStore(db *gateway.Client) *SupabaseDocumentStore {
+	return &SupabaseDocumentStore{db: db}
+}
+
+func (s *SupabaseDocumentStore) ListDocuments(ctx context.Context, petitionIDs ...string) ([]models.PetitionDocument, error) {
+	if len(petitionIDs) == 0 {
+		return nil, nil
+	}
+	var documents []models.PetitionDocument
+	if err := s.db.Select(ctx, "petition_documents", gateway.NewQuery().In("petition_id", petitionIDs...), &documents); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (s *SupabaseDocumentStore) ListAttachments(ctx context.Context, petitionIDs ...string) ([]models.PetitionAttachment, error) {
+	if len(petitionIDs) == 0 {
+		return nil, nil
+	}
+	var attachments []models.PetitionAttachment
+	if err := s.db.Select(ctx, "petition_attachments", gateway.NewQuery().In("petition_id", petitionIDs...), &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (s *SupabaseDocumentStore) ListComments(ctx context.Context, petitionIDs ...string) ([]models.PetitionComment, error) {
+	if len(petitionIDs) == 0 {
+		return nil, nil
+	}
+	var comments []models.PetitionComment
+	q := gateway.NewQuery().
+		Select("id", "petition_id", "author_id", "content", "created_at", "updated_at").
+		In("petition_id", petitionIDs...).
+		Order("created_at", false)
+	if err := s.db.Select(ctx, "petition_comments", q, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
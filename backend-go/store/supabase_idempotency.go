@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseIdempotencyStore keeps entries in public.idempotency_keys, so a
+// retry landing on another instance still gets the first response.
+type SupabaseIdempotencyStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseIdempotencyStore(db *gateway.Client) *SupabaseIdempotencyStore {
+	return &SupabaseIdempotencyStore{db: db}
+}
+
+func (s *SupabaseIdempotencyStore) Reserve(ctx context.Context, req *models.IdempotentRequest) (*models.IdempotentRequest, error) {
+	err := s.db.Insert(ctx, "idempotency_keys", req, nil)
+	if err == nil || !gateway.IsConflict(err) {
+		return nil, err
+	}
+
+	existing, err := s.get(ctx, req.Key)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if now.Before(existing.ExpiresAt) {
+		return existing, nil
+	}
+
+	// Expired but not cleaned up yet: take the row over, unless another
+	// request did it first. Not retried, like TakeOver: a retry of an update
+	// that landed would find our own reservation and report it in flight.
+	var taken []models.IdempotentRequest
+	q := gateway.NewQuery().
+		Eq("key", req.Key).
+		Lt("expires_at", now.UTC().Format(time.RFC3339Nano))
+	if err := s.db.UpdateOnce(ctx, "idempotency_keys", q, req, &taken); err != nil {
+		return nil, err
+	}
+	if len(taken) == 0 {
+		return s.get(ctx, req.Key)
+	}
+	return nil, nil
+}
+
+func (s *SupabaseIdempotencyStore) get(ctx context.Context, key string) (*models.IdempotentRequest, error) {
+	var rows []models.IdempotentRequest
+	if err := s.db.Select(ctx, "idempotency_keys", gateway.NewQuery().Eq("key", key), &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrNotFound
+	}
+	return &rows[0], nil
+}
+
+func (s *SupabaseIdempotencyStore) Complete(ctx context.Context, key string, status int, contentType, body string) error {
+	payload := map[string]interface{}{
+		"status":       status,
+		"content_type": contentType,
+		"body":         body,
+	}
+	return s.db.Update(ctx, "idempotency_keys", gateway.NewQuery().Eq("key", key), payload, nil)
+}
+
+// TakeOver is a single conditional update, so two retries cannot both win.
+// It is not retried: a retry of an update that landed would match nothing.
+func (s *SupabaseIdempotencyStore) TakeOver(ctx context.Context, req *models.IdempotentRequest, staleBefore time.Time) (bool, error) {
+	var taken []models.IdempotentRequest
+	q := gateway.NewQuery().
+		Eq("key", req.Key).
+		Eq("status", "0").
+		Lt("created_at", staleBefore.UTC().Format(time.RFC3339Nano))
+	if err := s.db.UpdateOnce(ctx, "idempotency_keys", q, req, &taken); err != nil {
+		return false, err
+	}
+	return len(taken) > 0, nil
+}
+
+func (s *SupabaseIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.db.Delete(ctx, "idempotency_keys", gateway.NewQuery().Eq("key", key))
+}
+
+func (s *SupabaseIdempotencyStore) DeleteExpired(ctx context.Context, t time.Time) error {
+	q := gateway.NewQuery().Lt("expires_at", t.UTC().Format(time.RFC3339))
+	return s.db.Delete(ctx, "idempotency_keys", q)
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseIdentityStore delegates credentials to GoTrue.
+type SupabaseIdentityStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseIdentityStore(db *gateway.Client) *SupabaseIdentityStore {
+	return &SupabaseIdentityStore{db: db}
+}
+
+type goTrueUserResponse struct {
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+}
+
+func (s *SupabaseIdentityStore) SignIn(ctx context.Context, email, password string) (string, error) {
+	payload := map[string]interface{}{
+		"email":    email,
+		"password": password,
+	}
+	var resp goTrueUserResponse
+	err := s.db.Auth(ctx, http.MethodPost, "/token?grant_type=password", payload, &resp)
+	var apiErr *models.ApiError
+	if errors.As(err, &apiErr) && (apiErr.Status == http.StatusBadRequest || apiErr.Status == http.StatusUnauthorized) {
+		return "", ErrInvalidCredentials
+	}
+	if err != nil {
+		return "", err
+	}
+	if resp.User.ID == "" {
+		return "", errors.New("gotrue: token response without user")
+	}
+	return resp.User.ID, nil
+}
+
+func (s *SupabaseIdentityStore) SignUp(ctx context.Context, email, password string, metadata map[string]interface{}) (string, error) {
+	payload := map[string]interface{}{
+		"email":    email,
+		"password": password,
+		"data":     metadata,
+	}
+	var resp goTrueUserResponse
+	if err := s.db.Auth(ctx, http.MethodPost, "/signup", payload, &resp); err != nil {
+		return "", err
+	}
+	if resp.User.ID == "" {
+		return "", errors.New("gotrue: signup response without user")
+	}
+	return resp.User.ID, nil
+}
+
+func (s *SupabaseIdentityStore) SendRecovery(ctx context.Context, email string) error {
+	payload := map[string]interface{}{"email": email}
+	return s.db.Auth(ctx, http.MethodPost, "/recover", payload, nil)
+}
+
+func (s *SupabaseIdentityStore) VerifyRecovery(ctx context.Context, email, token string) (string, error) {
+	payload := map[string]interface{}{"type": "recovery"}
+	if email != "" {
+		payload["email"] = email
+		payload["token"] = token
+	} else {
+		payload["token_hash"] = token
+	}
+	var resp goTrueUserResponse
+	if err := s.db.Auth(ctx, http.MethodPost, "/verify", payload, &resp); err != nil {
+		return "", err
+	}
+	if resp.User.ID == "" {
+		return "", errors.New("gotrue: verify response without user")
+	}
+	return resp.User.ID, nil
+}
+
+func (s *SupabaseIdentityStore) SetPassword(ctx context.Context, userID, password string) error {
+	payload := map[string]interface{}{"password": password}
+	return s.db.Auth(ctx, http.MethodPut, gateway.AdminUserPath(userID), payload, nil)
+}
+
+func (s *SupabaseIdentityStore) GetEmail(ctx context.Context, userID string) (string, error) {
+	var user struct {
+		Email string `json:"email"`
+	}
+	if err := s.db.Auth(ctx, http.MethodGet, gateway.AdminUserPath(userID), nil, &user); err != nil {
+		return "", err
+	}
+	return user.Email, nil
+}
+
+// DeleteUser relies on the cascade from auth.users to remove the profile.
+func (s *SupabaseIdentityStore) DeleteUser(ctx context.Context, userID string) error {
+	return s.db.Auth(ctx, http.MethodDelete, gateway.AdminUserPath(userID), nil, nil)
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseMFAStore keeps enrollments in public.user_mfa.
+type SupabaseMFAStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseMFAStore(db *gateway.Client) *SupabaseMFAStore {
+	return &SupabaseMFAStore{db: db}
+}
+
+func (s *SupabaseMFAStore) Get(ctx context.Context, userID string) (*models.MFAConfig, error) {
+	var configs []models.MFAConfig
+	if err := s.db.Select(ctx, "user_mfa", gateway.NewQuery().Eq("user_id", userID), &configs); err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		return nil, ErrNotFound
+	}
+	return &configs[0], nil
+}
+
+func (s *SupabaseMFAStore) Save(ctx context.Context, config *models.MFAConfig) error {
+	return s.db.Upsert(ctx, "user_mfa", config)
+}
+
+// Update is a conditional PATCH: the filters repeat prev, so the row is only
+// written if nobody changed it since it was read. Recovery code hashes are hex
+// and need no escaping inside the array literal.
+func (s *SupabaseMFAStore) Update(ctx context.Context, config, prev *models.MFAConfig) (bool, error) {
+	q := gateway.NewQuery().
+		Eq("user_id", config.UserID).
+		Eq("secret_encrypted", prev.SecretEncrypted).
+		Eq("enabled", strconv.FormatBool(prev.Enabled)).
+		Eq("last_used_step", strconv.FormatInt(prev.LastUsedStep, 10)).
+		Eq("recovery_code_hashes", "{"+strings.Join(prev.RecoveryCodeHashes, ",")+"}")
+	var updated []models.MFAConfig
+	// Two requests with the same code produce the same config, so a retry
+	// could not tell its own write from the other request's: no retries.
+	if err := s.db.UpdateOnce(ctx, "user_mfa", q, config, &updated); err != nil {
+		return false, err
+	}
+	return len(updated) > 0, nil
+}
+
+func (s *SupabaseMFAStore) Delete(ctx context.Context, userID string) error {
+	return s.db.Delete(ctx, "user_mfa", gateway.NewQuery().Eq("user_id", userID))
+}
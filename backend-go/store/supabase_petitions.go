@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabasePetitionStore keeps petitions in public.petitions.
+type SupabasePetitionStore struct {
+	db *gateway.Client
+}
+
+func NewSupabasePetitionStore(db *gateway.Client) *SupabasePetitionStore {
+	return &SupabasePetitionStore{db: db}
+}
+
+func (s *SupabasePetitionStore) Get(ctx context.Context, id string) (*models.Petition, error) {
+	var petitions []models.Petition
+	if err := s.db.Select(ctx, "petitions", gateway.NewQuery().Eq("id", id), &petitions); err != nil {
+		return nil, err
+	}
+	if len(petitions) == 0 {
+		return nil, ErrNotFound
+	}
+	return &petitions[0], nil
+}
+
+func (s *SupabasePetitionStore) ListByUser(ctx context.Context, userID string) ([]models.Petition, error) {
+	var petitions []models.Petition
+	q := gateway.NewQuery().Eq("user_id", userID).Order("created_at", true)
+	if err := s.db.Select(ctx, "petitions", q, &petitions); err != nil {
+		return nil, err
+	}
+	return petitions, nil
+}
+
+func (s *SupabasePetitionStore) List(ctx context.Context, filter PetitionFilter) ([]models.Petition, int, error) {
+	var conditions []string
+	if filter.UserID != "" {
+		conditions = append(conditions, gateway.Cond("user_id", "eq", filter.UserID))
+	}
+	if len(filter.TeamIDs) > 0 {
+		conditions = append(conditions, gateway.CondIn("team_id", filter.TeamIDs...))
+	}
+	if !filter.All && len(conditions) == 0 {
+		return []models.Petition{}, 0, nil
+	}
+	q := gateway.NewQuery()
+	if !filter.All {
+		q.Or(conditions...)
+	}
+	if filter.Status != "" {
+		q.Eq("status", filter.Status)
+	}
+	q.Order("created_at", !filter.Ascending).Offset(filter.Offset)
+	if filter.Limit > 0 {
+		q.Limit(filter.Limit)
+	}
+
+	petitions := []models.Petition{}
+	total, err := s.db.SelectCount(ctx, "petitions", q, &petitions)
+	if err != nil {
+		return nil, 0, err
+	}
+	return petitions, total, nil
+}
+
+// Create calls create_petition, which locks the payer's balance, inserts the
+// petition and its token_transactions debit in one transaction.
+func (s *SupabasePetitionStore) Create(ctx context.Context, petition *models.Petition, payerID string, cost int) error {
+	payload := map[string]interface{}{
+		"p_petition": petition,
+		"p_payer_id": payerID,
+		"p_cost":     cost,
+	}
+	err := s.db.RPC(ctx, "create_petition", payload, nil)
+	var apiErr *models.ApiError
+	if errors.As(err, &apiErr) && apiErr.Message == "insufficient_tokens" {
+		return ErrInsufficientTokens
+	}
+	return err
+}
+
+func (s *SupabasePetitionStore) Update(ctx context.Context, petition *models.Petition) error {
+	payload := map[string]interface{}{
+		"team_id":        petition.TeamID,
+		"title":          petition.Title,
+		"description":    petition.Description,
+		"content":        petition.Content,
+		"category":       petition.Category,
+		"legal_area":     petition.LegalArea,
+		"petition_type":  petition.PetitionType,
+		"target":         petition.Target,
+		"has_process":    petition.HasProcess,
+		"process_number": petition.ProcessNumber,
+		"form_type":      petition.FormType,
+		"form_answers":   petition.FormAnswers,
+		"status":         petition.Status,
+		"updated_at":     petition.UpdatedAt,
+	}
+	var updated []models.Petition
+	if err := s.db.Update(ctx, "petitions", gateway.NewQuery().Eq("id", petition.ID), payload, &updated); err != nil {
+		return err
+	}
+	if len(updated) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SupabasePetitionStore) Delete(ctx context.Context, id string) error {
+	return s.db.Delete(ctx, "petitions", gateway.NewQuery().Eq("id", id))
+}
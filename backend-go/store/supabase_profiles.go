@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseProfileStore reads public.profiles.
+type SupabaseProfileStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseProfileStore(db *gateway.Client) *SupabaseProfileStore {
+	return &SupabaseProfileStore{db: db}
+}
+
+func (s *SupabaseProfileStore) Get(ctx context.Context, id string) (*models.Profile, error) {
+	var profiles []models.Profile
+	q := gateway.NewQuery().
+		Select("id", "email", "name", "is_admin", "terms_accepted", "terms_accepted_at", "created_at", "updated_at").
+		Eq("id", id)
+	if err := s.db.Select(ctx, "profiles", q, &profiles); err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, ErrNotFound
+	}
+	return &profiles[0], nil
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseRefreshTokenStore keeps sessions in public.auth_sessions and tokens
+// in public.refresh_tokens.
+type SupabaseRefreshTokenStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseRefreshTokenStore(db *gateway.Client) *SupabaseRefreshTokenStore {
+	return &SupabaseRefreshTokenStore{db: db}
+}
+
+func (s *SupabaseRefreshTokenStore) CreateSession(ctx context.Context, session *models.Session) error {
+	return s.db.Insert(ctx, "auth_sessions", session, nil)
+}
+
+func (s *SupabaseRefreshTokenStore) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	var sessions []models.Session
+	if err := s.db.Select(ctx, "auth_sessions", gateway.NewQuery().Eq("id", id), &sessions); err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, ErrNotFound
+	}
+	return &sessions[0], nil
+}
+
+func (s *SupabaseRefreshTokenStore) ListUserSessions(ctx context.Context, userID string) ([]models.Session, error) {
+	var sessions []models.Session
+	q := gateway.NewQuery().
+		Eq("user_id", userID).
+		IsNull("revoked_at").
+		Gt("expires_at", time.Now().UTC().Format(time.RFC3339)).
+		Order("last_seen_at", true)
+	if err := s.db.Select(ctx, "auth_sessions", q, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *SupabaseRefreshTokenStore) TouchSession(ctx context.Context, id string, at time.Time, ipAddress, userAgent string) error {
+	payload := map[string]interface{}{
+		"last_seen_at": at.UTC(),
+		"ip_address":   ipAddress,
+		"user_agent":   userAgent,
+	}
+	return s.db.Update(ctx, "auth_sessions", gateway.NewQuery().Eq("id", id), payload, nil)
+}
+
+func (s *SupabaseRefreshTokenStore) RevokeSession(ctx context.Context, id string, at time.Time) error {
+	q := gateway.NewQuery().Eq("id", id).IsNull("revoked_at")
+	payload := map[string]interface{}{"revoked_at": at.UTC()}
+	return s.db.Update(ctx, "auth_sessions", q, payload, nil)
+}
+
+func (s *SupabaseRefreshTokenStore) RevokeUserSessions(ctx context.Context, userID string, at time.Time) error {
+	q := gateway.NewQuery().Eq("user_id", userID).IsNull("revoked_at")
+	payload := map[string]interface{}{"revoked_at": at.UTC()}
+	return s.db.Update(ctx, "auth_sessions", q, payload, nil)
+}
+
+func (s *SupabaseRefreshTokenStore) CreateToken(ctx context.Context, token *models.RefreshToken) error {
+	return s.db.Insert(ctx, "refresh_tokens", token, nil)
+}
+
+func (s *SupabaseRefreshTokenStore) GetTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var tokens []models.RefreshToken
+	if err := s.db.Select(ctx, "refresh_tokens", gateway.NewQuery().Eq("token_hash", hash), &tokens); err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrNotFound
+	}
+	return &tokens[0], nil
+}
+
+func (s *SupabaseRefreshTokenStore) MarkTokenUsed(ctx context.Context, id string, at time.Time) (bool, error) {
+	// The used_at=is.null filter makes the update conditional, so only one of
+	// two concurrent refreshes with the same token can win.
+	// Postgres keeps microseconds; truncating lets the stored value be
+	// compared with at below.
+	at = at.UTC().Truncate(time.Microsecond)
+	var updated []models.RefreshToken
+	q := gateway.NewQuery().Eq("id", id).IsNull("used_at")
+	payload := map[string]interface{}{"used_at": at}
+	if err := s.db.Update(ctx, "refresh_tokens", q, payload, &updated); err != nil {
+		return false, err
+	}
+	if len(updated) > 0 {
+		return true, nil
+	}
+
+	// The gateway retries a PATCH that timed out; when the first attempt did
+	// land, the retry matches nothing. Our own used_at tells that case apart
+	// from a replay.
+	var tokens []models.RefreshToken
+	if err := s.db.Select(ctx, "refresh_tokens", gateway.NewQuery().Eq("id", id), &tokens); err != nil {
+		return false, err
+	}
+	if len(tokens) == 0 {
+		return false, ErrNotFound
+	}
+	return tokens[0].UsedAt != nil && tokens[0].UsedAt.Equal(at), nil
+}
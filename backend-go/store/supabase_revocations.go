@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/utils"
+)
+
+// SupabaseRevocationList keeps revocations in public.revoked_tokens so every
+// backend instance sees them.
+type SupabaseRevocationList struct {
+	db *gateway.Client
+}
+
+func NewSupabaseRevocationList(db *gateway.Client) *SupabaseRevocationList {
+	return &SupabaseRevocationList{db: db}
+}
+
+type revokedTokenRow struct {
+	Key       string    `json:"key"`
+	RevokedAt time.Time `json:"revoked_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (l *SupabaseRevocationList) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return l.put(ctx, tokenRevocationKey(jti), time.Now(), expiresAt)
+}
+
+func (l *SupabaseRevocationList) RevokeSession(ctx context.Context, sessionID string, expiresAt time.Time) error {
+	return l.put(ctx, sessionRevocationKey(sessionID), time.Now(), expiresAt)
+}
+
+func (l *SupabaseRevocationList) RevokeUserTokens(ctx context.Context, userID string, cutoff time.Time) error {
+	return l.put(ctx, userRevocationKey(userID), cutoff, cutoff.Add(utils.AccessTokenTTL))
+}
+
+func (l *SupabaseRevocationList) IsRevoked(ctx context.Context, jti, sessionID, userID string, issuedAt time.Time) (bool, error) {
+	keys := []string{tokenRevocationKey(jti), userRevocationKey(userID)}
+	if sessionID != "" {
+		keys = append(keys, sessionRevocationKey(sessionID))
+	}
+
+	var rows []revokedTokenRow
+	q := gateway.NewQuery().
+		In("key", keys...).
+		Gt("expires_at", time.Now().UTC().Format(time.RFC3339))
+	if err := l.db.Select(ctx, "revoked_tokens", q, &rows); err != nil {
+		return false, err
+	}
+
+	userKey := userRevocationKey(userID)
+	for _, row := range rows {
+		if row.Key != userKey || !issuedAt.After(row.RevokedAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (l *SupabaseRevocationList) put(ctx context.Context, key string, revokedAt, expiresAt time.Time) error {
+	row := revokedTokenRow{
+		Key:       key,
+		RevokedAt: revokedAt.UTC().Truncate(time.Millisecond),
+		ExpiresAt: expiresAt.UTC(),
+	}
+	return l.db.Upsert(ctx, "revoked_tokens", row)
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseStatsStore calls public.get_admin_stats.
+type SupabaseStatsStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseStatsStore(db *gateway.Client) *SupabaseStatsStore {
+	return &SupabaseStatsStore{db: db}
+}
+
+func (s *SupabaseStatsStore) Admin(ctx context.Context) (*models.AdminStats, error) {
+	var rows []models.AdminStats
+	if err := s.db.RPC(ctx, "get_admin_stats", map[string]interface{}{}, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &models.AdminStats{DistributionByStatus: map[string]int{}}, nil
+	}
+	if rows[0].DistributionByStatus == nil {
+		rows[0].DistributionByStatus = map[string]int{}
+	}
+	return &rows[0], nil
+}
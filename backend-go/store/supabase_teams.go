@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// inviteRejectionCodes are the exceptions raised by accept_team_invite.
+var inviteRejectionCodes = []string{
+	"invite_not_found",
+	"invite_not_pending",
+	"invite_expired",
+	"invite_email_mismatch",
+}
+
+// SupabaseTeamStore keeps teams in public.teams and invites in
+// public.team_invites.
+type SupabaseTeamStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseTeamStore(db *gateway.Client) *SupabaseTeamStore {
+	return &SupabaseTeamStore{db: db}
+}
+
+func (s *SupabaseTeamStore) Get(ctx context.Context, id string) (*models.Team, error) {
+	var teams []models.Team
+	if err := s.db.Select(ctx, "teams", gateway.NewQuery().Eq("id", id), &teams); err != nil {
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return nil, ErrNotFound
+	}
+	return &teams[0], nil
+}
+
+func (s *SupabaseTeamStore) Create(ctx context.Context, team *models.Team) error {
+	return s.db.Insert(ctx, "teams", team, nil)
+}
+
+func (s *SupabaseTeamStore) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.db.Delete(ctx, "teams", gateway.NewQuery().In("id", ids...))
+}
+
+func (s *SupabaseTeamStore) GetInvite(ctx context.Context, id string) (*models.TeamInvite, error) {
+	var invites []models.TeamInvite
+	if err := s.db.Select(ctx, "team_invites", gateway.NewQuery().Eq("id", id), &invites); err != nil {
+		var apiErr *models.ApiError
+		// Ids que não são uuid fazem o PostgREST responder 400
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusBadRequest {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if len(invites) == 0 {
+		return nil, ErrNotFound
+	}
+	return &invites[0], nil
+}
+
+func (s *SupabaseTeamStore) AcceptInvite(ctx context.Context, inviteID, userID, email string) error {
+	payload := map[string]interface{}{
+		"p_invite_id": inviteID,
+		"p_user_id":   userID,
+		"p_email":     email,
+	}
+	err := s.db.RPC(ctx, "accept_team_invite", payload, nil)
+	var apiErr *models.ApiError
+	if errors.As(err, &apiErr) {
+		for _, code := range inviteRejectionCodes {
+			if apiErr.Message == code {
+				return &InviteRejectedError{Code: code}
+			}
+		}
+	}
+	return err
+}
+
+// SupabaseTeamMemberStore keeps memberships in public.team_members.
+type SupabaseTeamMemberStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseTeamMemberStore(db *gateway.Client) *SupabaseTeamMemberStore {
+	return &SupabaseTeamMemberStore{db: db}
+}
+
+func (s *SupabaseTeamMemberStore) ListByUser(ctx context.Context, userID string) ([]models.TeamMember, error) {
+	var members []models.TeamMember
+	if err := s.db.Select(ctx, "team_members", gateway.NewQuery().Eq("user_id", userID), &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (s *SupabaseTeamMemberStore) ListByTeams(ctx context.Context, teamIDs ...string) ([]models.TeamMember, error) {
+	if len(teamIDs) == 0 {
+		return nil, nil
+	}
+	var members []models.TeamMember
+	if err := s.db.Select(ctx, "team_members", gateway.NewQuery().In("team_id", teamIDs...), &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (s *SupabaseTeamMemberStore) Add(ctx context.Context, member *models.TeamMember) error {
+	return s.db.Insert(ctx, "team_members", member, nil)
+}
@@ -0,0 +1,29 @@
+package store
+
+import (
+	"context"
+
+	"argumentum-backend/internal/gateway"
+	"argumentum-backend/models"
+)
+
+// SupabaseTokenStore reads public.user_tokens.
+type SupabaseTokenStore struct {
+	db *gateway.Client
+}
+
+func NewSupabaseTokenStore(db *gateway.Client) *SupabaseTokenStore {
+	return &SupabaseTokenStore{db: db}
+}
+
+func (s *SupabaseTokenStore) Balance(ctx context.Context, userID string) (int, error) {
+	var rows []models.UserTokens
+	q := gateway.NewQuery().Select("user_id", "tokens").Eq("user_id", userID)
+	if err := s.db.Select(ctx, "user_tokens", q, &rows); err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Tokens, nil
+}
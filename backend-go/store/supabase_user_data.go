@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"argumentum-backend/internal/gateway"
+)
+
+// userDataPageSize matches PostgREST's default max_rows. A lower server limit
+// only means more pages: the total decides when to stop.
+const userDataPageSize = 1000
+
+// SupabaseUserDataStore reads any public table and Supabase Storage.
+type SupabaseUserDataStore struct {
+	db       *gateway.Client
+	pageSize int
+}
+
+func NewSupabaseUserDataStore(db *gateway.Client) *SupabaseUserDataStore {
+	return &SupabaseUserDataStore{db: db, pageSize: userDataPageSize}
+}
+
+// Rows pages through the matches ordered by id, counting them with the first
+// page, and fails when fewer rows than counted come back.
+func (s *SupabaseUserDataStore) Rows(ctx context.Context, table, column string, values ...string) ([]json.RawMessage, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	var rows []json.RawMessage
+	total := -1
+	for total < 0 || len(rows) < total {
+		q := gateway.NewQuery().In(column, values...).Order("id", false).Limit(s.pageSize).Offset(len(rows))
+		var page []json.RawMessage
+		var err error
+		if total < 0 {
+			total, err = s.db.SelectCount(ctx, table, q, &page)
+		} else {
+			err = s.db.Select(ctx, table, q, &page)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		rows = append(rows, page...)
+	}
+	if len(rows) < total {
+		return nil, fmt.Errorf("%s: read %d of %d rows", table, len(rows), total)
+	}
+	return rows, nil
+}
+
+func (s *SupabaseUserDataStore) StorageObject(ctx context.Context, bucket, path string) (io.ReadCloser, error) {
+	return s.db.StorageObject(ctx, bucket, path)
+}
+
+func (s *SupabaseUserDataStore) SettingsStorageKeys(ctx context.Context, userID string) ([]string, error) {
+	var settings []struct {
+		Logo               string `json:"logo_r2_key"`
+		LetterheadTemplate string `json:"letterhead_template_r2_key"`
+		PetitionTemplate   string `json:"petition_template_r2_key"`
+	}
+	q := gateway.NewQuery().
+		Select("logo_r2_key", "letterhead_template_r2_key", "petition_template_r2_key").
+		Eq("user_id", userID)
+	if err := s.db.Select(ctx, "petition_settings", q, &settings); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, row := range settings {
+		for _, key := range []string{row.Logo, row.LetterheadTemplate, row.PetitionTemplate} {
+			if key != "" {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// Erase runs delete_user_data, which removes everything in one transaction;
+// the rows of the newer tables go with auth.users by cascade.
+func (s *SupabaseUserDataStore) Erase(ctx context.Context, userID string) error {
+	payload := map[string]interface{}{"p_user_id": userID}
+	if err := s.db.RPC(ctx, "delete_user_data", payload, nil); err != nil {
+		return fmt.Errorf("delete_user_data: %w", err)
+	}
+	return nil
+}
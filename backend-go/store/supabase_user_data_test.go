@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"argumentum-backend/internal/gateway"
+)
+
+// newPostgREST serves stored rows of any table and counts total of them,
+// cutting every response at maxRows like PostgREST's max_rows setting does.
+func newPostgREST(t *testing.T, stored, total, maxRows int) *gateway.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		if limit == 0 || limit > maxRows {
+			limit = maxRows
+		}
+		var rows []map[string]string
+		for i := offset; i < stored && len(rows) < limit; i++ {
+			rows = append(rows, map[string]string{"id": fmt.Sprintf("row-%04d", i)})
+		}
+		if r.Header.Get("Prefer") == "count=exact" {
+			w.Header().Set("Content-Range", fmt.Sprintf("%d-%d/%d", offset, offset+len(rows)-1, total))
+		}
+		json.NewEncoder(w).Encode(rows)
+	}))
+	t.Cleanup(srv.Close)
+	return gateway.New(gateway.Config{URL: srv.URL, ServiceKey: "key", MaxRetries: 0, BaseBackoff: time.Millisecond})
+}
+
+func TestSupabaseUserDataRowsPagesPastServerLimit(t *testing.T) {
+	s := NewSupabaseUserDataStore(newPostgREST(t, 2503, 2503, 1000))
+
+	rows, err := s.Rows(context.Background(), "petitions", "user_id", "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2503 {
+		t.Fatalf("rows = %d, want 2503", len(rows))
+	}
+	var last struct{ ID string }
+	if err := json.Unmarshal(rows[len(rows)-1], &last); err != nil || last.ID != "row-2502" {
+		t.Fatalf("last row = %s, %v", rows[len(rows)-1], err)
+	}
+}
+
+// A server limit below the page size only means more pages.
+func TestSupabaseUserDataRowsWithLowerServerLimit(t *testing.T) {
+	s := NewSupabaseUserDataStore(newPostgREST(t, 1200, 1200, 500))
+
+	rows, err := s.Rows(context.Background(), "petitions", "user_id", "u1")
+	if err != nil || len(rows) != 1200 {
+		t.Fatalf("rows = %d, %v; want 1200", len(rows), err)
+	}
+}
+
+// Rows deleted between pages leave the export short of the count; that fails
+// instead of shipping an incomplete file.
+func TestSupabaseUserDataRowsFailsShortOfCount(t *testing.T) {
+	s := NewSupabaseUserDataStore(newPostgREST(t, 1500, 1600, 1000))
+
+	if rows, err := s.Rows(context.Background(), "petitions", "user_id", "u1"); err == nil {
+		t.Fatalf("rows = %d, want an error for 1500 of 1600", len(rows))
+	}
+}
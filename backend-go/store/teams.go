@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"argumentum-backend/models"
+)
+
+// InviteRejectedError is returned by TeamStore.AcceptInvite when the invite
+// cannot be accepted. Code is one of the exceptions raised by the
+// accept_team_invite function (invite_not_found, invite_expired, ...).
+type InviteRejectedError struct {
+	Code string
+}
+
+func (e *InviteRejectedError) Error() string {
+	return fmt.Sprintf("invite rejected: %s", e.Code)
+}
+
+// TeamStore persists teams and their invites.
+type TeamStore interface {
+	Get(ctx context.Context, id string) (*models.Team, error)
+	Create(ctx context.Context, team *models.Team) error
+	// Delete removes the teams with their members.
+	Delete(ctx context.Context, ids ...string) error
+	GetInvite(ctx context.Context, id string) (*models.TeamInvite, error)
+	// AcceptInvite adds the user to the invite's team with the invited role
+	// and marks the invite accepted, atomically.
+	AcceptInvite(ctx context.Context, inviteID, userID, email string) error
+}
+
+// TeamMemberStore persists team memberships.
+type TeamMemberStore interface {
+	ListByUser(ctx context.Context, userID string) ([]models.TeamMember, error)
+	ListByTeams(ctx context.Context, teamIDs ...string) ([]models.TeamMember, error)
+	Add(ctx context.Context, member *models.TeamMember) error
+}
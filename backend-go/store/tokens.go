@@ -0,0 +1,9 @@
+package store
+
+import "context"
+
+// TokenStore reads token balances from public.user_tokens.
+type TokenStore interface {
+	// Balance returns the user's tokens, 0 when the user never had any.
+	Balance(ctx context.Context, userID string) (int, error)
+}
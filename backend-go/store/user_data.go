@@ -0,0 +1,25 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// UserDataStore reads the raw rows and files held about a user, for the LGPD
+// data export, and erases them when the account is deleted.
+type UserDataStore interface {
+	// Rows returns every row of table whose column equals one of values,
+	// as stored. Large tables are read in pages, so nothing is cut off by
+	// the server's row limit.
+	Rows(ctx context.Context, table, column string, values ...string) ([]json.RawMessage, error)
+	// StorageObject streams a file uploaded to Supabase Storage. The caller
+	// closes the reader.
+	StorageObject(ctx context.Context, bucket, path string) (io.ReadCloser, error)
+	// SettingsStorageKeys lists the R2 objects referenced by the user's
+	// petition settings (logo and templates).
+	SettingsStorageKeys(ctx context.Context, userID string) ([]string, error)
+	// Erase removes the user's rows, from petitions and their files'
+	// metadata down to the login itself. Erasing twice is not an error.
+	Erase(ctx context.Context, userID string) error
+}
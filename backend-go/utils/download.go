@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PurposeDownload marks tokens that only authorize downloading one file.
+const PurposeDownload = "download"
+
+type downloadClaims struct {
+	Resource string `json:"resource"`
+	Purpose  string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// GenerateDownloadToken issues a token for a time-limited download link to
+// resource. Being signed, it needs no server-side state.
+func GenerateDownloadToken(resource string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &downloadClaims{
+		Resource: resource,
+		Purpose:  PurposeDownload,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        GenerateID(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "argumentum-backend",
+		},
+	}
+	return keySet.Sign(claims)
+}
+
+// ValidateDownloadToken checks that the token is unexpired and was issued for
+// resource.
+func ValidateDownloadToken(tokenString, resource string) error {
+	claims := &downloadClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, keySet.Keyfunc,
+		jwt.WithValidMethods(keySet.ValidMethods()))
+	if err != nil {
+		return err
+	}
+	if !token.Valid || claims.ExpiresAt == nil || claims.Purpose != PurposeDownload || claims.Resource != resource {
+		return errors.New("invalid download token")
+	}
+	return nil
+}
@@ -1,60 +1,209 @@
-
 package utils
 
 import (
+	"context"
 	"errors"
-	"os"
+	"log"
+	"log/slog"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret []byte
+var (
+	keySet           *KeySet
+	keyRotationEvery time.Duration
+)
 
 func init() {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		// Default secret for development - change in production
-		secret = "argumentum-default-jwt-secret-change-in-production"
+	// iat em milissegundos: um token emitido logo depois de um "sair de
+	// todas as sessões" não cai no mesmo instante do corte.
+	jwt.TimePrecision = time.Millisecond
+
+	// Ephemeral EdDSA key until main.go calls ConfigureKeys, so tests and
+	// tools can sign tokens without any setup.
+	var err error
+	keySet, err = NewKeySet("EdDSA", "")
+	if err != nil {
+		log.Fatalf("Erro ao gerar chave JWT: %v", err)
+	}
+}
+
+// ConfigureKeys replaces the signing keys with the ones in dir (or a fresh
+// ephemeral key when dir is empty) and sets the scheduled rotation interval.
+func ConfigureKeys(alg, dir string, rotateEvery time.Duration) error {
+	if dir == "" {
+		// Without a key directory the key lives only in memory: every restart
+		// invalidates issued tokens. Fine for development only.
+		slog.Warn("JWT_KEYS_DIR não definido, usando chave de assinatura efêmera")
+	}
+	ks, err := NewKeySet(alg, dir)
+	if err != nil {
+		return err
+	}
+	keySet = ks
+	keyRotationEvery = rotateEvery
+	return nil
+}
+
+// Keys returns the key set used to sign and verify access tokens.
+func Keys() *KeySet {
+	return keySet
+}
+
+// RunKeyRotation runs scheduled rotation when JWT_KEY_ROTATION_INTERVAL is
+// set, until ctx is cancelled. It returns right away otherwise.
+func RunKeyRotation(ctx context.Context) {
+	if keyRotationEvery > 0 {
+		keySet.RunRotation(ctx, keyRotationEvery)
 	}
-	jwtSecret = []byte(secret)
 }
 
+// AccessTokenTTL is how long an access token stays valid unless revoked.
+const AccessTokenTTL = 24 * time.Hour
+
+// MFAPendingTTL bounds the second login step once the password was accepted.
+const MFAPendingTTL = 5 * time.Minute
+
+// PurposeMFAPending marks tokens that only allow completing a 2FA login.
+const PurposeMFAPending = "mfa_pending"
+
 type Claims struct {
-	UserID string `json:"user_id"`
+	UserID    string `json:"user_id"`
+	SessionID string `json:"sid,omitempty"`
+	IsAdmin   bool   `json:"is_admin"`
+	// Teams maps team id to the user's role in that team.
+	Teams map[string]string `json:"teams,omitempty"`
+	// Purpose is empty for access tokens; restricted tokens set it.
+	Purpose string `json:"purpose,omitempty"`
+	// InviteID carries a team invite across the 2FA step of a login.
+	InviteID string `json:"invite_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(userID string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
-	
+// Identity is what an access token asserts about its bearer.
+type Identity struct {
+	UserID    string
+	SessionID string
+	IsAdmin   bool
+	Teams     map[string]string
+
+	// APIKeyID is set when the identity comes from an API key, and
+	// APIKeyTeamID when that key is scoped to a team. Neither goes into a
+	// token.
+	APIKeyID     string
+	APIKeyTeamID string
+
+	// IssuedAfter, when set, moves iat past that instant: a token issued in
+	// the same millisecond as a "revoke everything" cutoff would otherwise
+	// fall under it. It does not go into a token either.
+	IssuedAfter time.Time
+}
+
+// exactIssuedAt returns the first instant from t whose iat reads back
+// unchanged. The library decodes iat through a float64 and truncates, so
+// some milliseconds come back one less, which could put a token issued right
+// after a cutoff under it.
+func exactIssuedAt(t time.Time) time.Time {
+	for i := 0; i < 10; i++ {
+		encoded, err := jwt.NewNumericDate(t).MarshalJSON()
+		if err != nil {
+			break
+		}
+		var decoded jwt.NumericDate
+		if err := decoded.UnmarshalJSON(encoded); err == nil && decoded.Equal(t) {
+			break
+		}
+		t = t.Add(jwt.TimePrecision)
+	}
+	return t
+}
+
+// GenerateJWT issues an access token for the identity, bound to the session
+// that produced it so logout can revoke both together. Roles are a snapshot
+// taken at issuance and refreshed on every token refresh.
+func GenerateJWT(identity Identity) (string, error) {
+	issuedAt := time.Now().Truncate(jwt.TimePrecision)
+	if !issuedAt.After(identity.IssuedAfter) {
+		issuedAt = identity.IssuedAfter.Truncate(jwt.TimePrecision).Add(jwt.TimePrecision)
+	}
+	issuedAt = exactIssuedAt(issuedAt)
+	expirationTime := issuedAt.Add(AccessTokenTTL)
+
 	claims := &Claims{
-		UserID: userID,
+		UserID:    identity.UserID,
+		SessionID: identity.SessionID,
+		IsAdmin:   identity.IsAdmin,
+		Teams:     identity.Teams,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        GenerateID(),
+			Subject:   identity.UserID,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
 			Issuer:    "argumentum-backend",
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return keySet.Sign(claims)
+}
+
+// GenerateMFAPendingJWT issues the short-lived token exchanged at
+// /auth/login/mfa. It is rejected everywhere else. inviteID, when set, is
+// accepted once the second factor checks out.
+func GenerateMFAPendingJWT(userID, inviteID string) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:   userID,
+		Purpose:  PurposeMFAPending,
+		InviteID: inviteID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        GenerateID(),
+			Subject:   userID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(MFAPendingTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "argumentum-backend",
+		},
+	}
+	return keySet.Sign(claims)
+}
+
+// ValidateJWT validates an access token.
+func ValidateJWT(tokenString string) (*Claims, error) {
+	claims, err := parseJWT(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, errors.New("token is not an access token")
+	}
+	return claims, nil
+}
+
+// ValidateMFAPendingJWT validates a token issued by GenerateMFAPendingJWT.
+func ValidateMFAPendingJWT(tokenString string) (*Claims, error) {
+	claims, err := parseJWT(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != PurposeMFAPending {
+		return nil, errors.New("token is not an MFA token")
+	}
+	return claims, nil
 }
 
-func ValidateJWT(tokenString string) (string, error) {
+func parseJWT(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, keySet.Keyfunc,
+		jwt.WithValidMethods(keySet.ValidMethods()))
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if !token.Valid {
-		return "", errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
 
-	return claims.UserID, nil
+	return claims, nil
 }
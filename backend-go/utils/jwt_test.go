@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// Whatever millisecond the cutoff falls on, the token read back is issued
+// after it; some iat values lose a millisecond in the library's float decode.
+func TestGenerateJWTIssuedAfterCutoff(t *testing.T) {
+	base := time.Now().Add(time.Hour).Truncate(time.Second)
+	for i := 0; i < 2000; i++ {
+		cutoff := base.Add(time.Duration(i) * time.Millisecond)
+		token, err := GenerateJWT(Identity{UserID: "u1", IssuedAfter: cutoff})
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims, err := ValidateJWT(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !claims.IssuedAt.After(cutoff) {
+			t.Fatalf("cutoff %s: iat %s read back not after it",
+				cutoff.Format(time.RFC3339Nano), claims.IssuedAt.Format(time.RFC3339Nano))
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKey is one key pair of the key set. A key stays available for
+// verification for AccessTokenTTL after a newer key replaced it.
+type signingKey struct {
+	ID        string
+	Private   crypto.Signer
+	CreatedAt time.Time
+	RetiredAt time.Time
+}
+
+// KeySet holds the active signing key plus every key whose tokens may still
+// be in circulation.
+type KeySet struct {
+	mu     sync.RWMutex
+	alg    string
+	dir    string
+	keys   map[string]*signingKey
+	active *signingKey
+}
+
+// NewKeySet loads the PEM (PKCS#8) private keys found in dir, named
+// <kid>.pem; the most recent one signs. With no dir, or an empty one, a key is
+// generated (and written to dir when set).
+func NewKeySet(alg, dir string) (*KeySet, error) {
+	if alg != "EdDSA" && alg != "RS256" {
+		return nil, fmt.Errorf("algoritmo JWT não suportado: %s", alg)
+	}
+
+	ks := &KeySet{alg: alg, dir: dir, keys: make(map[string]*signingKey)}
+	if dir != "" {
+		if err := ks.Reload(); err != nil {
+			return nil, err
+		}
+	}
+	if ks.active == nil {
+		if err := ks.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+// Rotate generates a new signing key. The previous key keeps verifying the
+// tokens it signed until they expire.
+func (ks *KeySet) Rotate() error {
+	key, err := generateSigningKey(ks.alg)
+	if err != nil {
+		return err
+	}
+	if ks.dir != "" {
+		if err := writeSigningKey(ks.dir, key); err != nil {
+			return err
+		}
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.active != nil {
+		ks.active.RetiredAt = key.CreatedAt
+	}
+	ks.keys[key.ID] = key
+	ks.active = key
+	slog.Info("Nova chave de assinatura JWT ativa", "kid", key.ID)
+	return nil
+}
+
+// Reload re-reads the key directory so instances sharing it pick up keys
+// rotated elsewhere.
+func (ks *KeySet) Reload() error {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var loaded []*signingKey
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pem" {
+			continue
+		}
+		key, err := readSigningKey(filepath.Join(ks.dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("chave %s: %w", entry.Name(), err)
+		}
+		if !keyMatchesAlg(key, ks.alg) {
+			return fmt.Errorf("chave %s não é compatível com %s", entry.Name(), ks.alg)
+		}
+		loaded = append(loaded, key)
+	}
+	if len(loaded) == 0 {
+		return nil
+	}
+
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].CreatedAt.Before(loaded[j].CreatedAt) })
+	for i := 0; i < len(loaded)-1; i++ {
+		loaded[i].RetiredAt = loaded[i+1].CreatedAt
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys = make(map[string]*signingKey, len(loaded))
+	for _, key := range loaded {
+		ks.keys[key.ID] = key
+	}
+	ks.active = loaded[len(loaded)-1]
+	return nil
+}
+
+// RunRotation rotates the signing key every interval and prunes keys that
+// can no longer have valid tokens, until ctx is cancelled.
+func (ks *KeySet) RunRotation(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if ks.dir != "" {
+			if err := ks.Reload(); err != nil {
+				slog.Error("Error reloading JWT keys", "error", err)
+			}
+		}
+		ks.mu.RLock()
+		due := time.Since(ks.active.CreatedAt) >= interval
+		ks.mu.RUnlock()
+		if due {
+			if err := ks.Rotate(); err != nil {
+				slog.Error("Error rotating JWT key", "error", err)
+			}
+		}
+		ks.prune()
+	}
+}
+
+func (ks *KeySet) prune() {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	for id, key := range ks.keys {
+		if key == ks.active || key.RetiredAt.IsZero() || time.Since(key.RetiredAt) < AccessTokenTTL {
+			continue
+		}
+		delete(ks.keys, id)
+		if ks.dir != "" {
+			if err := os.Remove(filepath.Join(ks.dir, id+".pem")); err != nil && !errors.Is(err, os.ErrNotExist) {
+				slog.Error("Error removing JWT key", "kid", id, "error", err)
+			}
+		}
+	}
+}
+
+func (ks *KeySet) method() jwt.SigningMethod {
+	if ks.alg == "RS256" {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+// Sign signs the claims with the active key, setting the kid header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	ks.mu.RLock()
+	key := ks.active
+	ks.mu.RUnlock()
+
+	token := jwt.NewWithClaims(ks.method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// Keyfunc resolves the verification key from the token's kid header.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	ks.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("chave desconhecida: %q", kid)
+	}
+	return key.Private.Public(), nil
+}
+
+// ValidMethods lists the algorithms accepted when parsing tokens.
+func (ks *KeySet) ValidMethods() []string {
+	return []string{ks.alg}
+}
+
+// JWK is the public part of a signing key (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns every verification key, for /.well-known/jwks.json.
+func (ks *KeySet) JWKS() JWKSet {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	set := JWKSet{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, key := range ks.keys {
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: ks.alg}
+		switch pub := key.Private.Public().(type) {
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+func generateSigningKey(alg string) (*signingKey, error) {
+	var private crypto.Signer
+	var err error
+	if alg == "RS256" {
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	} else {
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newSigningKey(private, time.Now())
+}
+
+func keyMatchesAlg(key *signingKey, alg string) bool {
+	switch key.Private.(type) {
+	case ed25519.PrivateKey:
+		return alg == "EdDSA"
+	case *rsa.PrivateKey:
+		return alg == "RS256"
+	}
+	return false
+}
+
+// newSigningKey derives the kid of a generated key from its public key.
+func newSigningKey(private crypto.Signer, createdAt time.Time) (*signingKey, error) {
+	der, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(der)
+	return &signingKey{
+		ID:        base64.RawURLEncoding.EncodeToString(sum[:12]),
+		Private:   private,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+func readSigningKey(path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("PEM inválido")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("tipo de chave não suportado")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := newSigningKey(private, info.ModTime())
+	if err != nil {
+		return nil, err
+	}
+	// Operators may name key files freely; the file name is the kid.
+	key.ID = strings.TrimSuffix(filepath.Base(path), ".pem")
+	return key, nil
+}
+
+func writeSigningKey(dir string, key *signingKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.Private)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	return os.WriteFile(filepath.Join(dir, key.ID+".pem"), data, 0o600)
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PurposeDeletionReceipt marks account deletion receipts. The purpose claim
+// keeps them from ever being accepted as access tokens.
+const PurposeDeletionReceipt = "account_deletion_receipt"
+
+// DeletionReceipt is the proof handed to a user whose account was erased
+// under the LGPD. It is signed like an access token, so it can be checked
+// against /.well-known/jwks.json while the signing key is published.
+type DeletionReceipt struct {
+	RequestID             string    `json:"request_id"`
+	UserID                string    `json:"user_id"`
+	EmailSHA256           string    `json:"email_sha256"`
+	RequestedAt           time.Time `json:"requested_at"`
+	CompletedAt           time.Time `json:"completed_at"`
+	Erased                []string  `json:"erased"`
+	StorageObjectsDeleted int       `json:"storage_objects_deleted"`
+	StorageObjectsFailed  int       `json:"storage_objects_failed"`
+}
+
+type deletionReceiptClaims struct {
+	DeletionReceipt
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// SignDeletionReceipt returns the receipt as a JWS signed with the active key.
+func SignDeletionReceipt(receipt DeletionReceipt) (string, error) {
+	claims := &deletionReceiptClaims{
+		DeletionReceipt: receipt,
+		Purpose:         PurposeDeletionReceipt,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       receipt.RequestID,
+			Subject:  receipt.UserID,
+			IssuedAt: jwt.NewNumericDate(receipt.CompletedAt),
+			Issuer:   "argumentum-backend",
+		},
+	}
+	return keySet.Sign(claims)
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// ErrSecretKeyMissing is returned when MFA_ENCRYPTION_KEY is not configured.
+var ErrSecretKeyMissing = errors.New("MFA_ENCRYPTION_KEY não configurada")
+
+var secretBoxKey []byte
+
+// SetSecretKey sets the key (MFA_ENCRYPTION_KEY) used to seal secrets. An
+// empty key leaves EncryptSecret and DecryptSecret failing with
+// ErrSecretKeyMissing.
+func SetSecretKey(raw string) {
+	if raw == "" {
+		secretBoxKey = nil
+		return
+	}
+	key := sha256.Sum256([]byte(raw))
+	secretBoxKey = key[:]
+}
+
+func secretKey() ([]byte, error) {
+	if secretBoxKey == nil {
+		return nil, ErrSecretKeyMissing
+	}
+	return secretBoxKey, nil
+}
+
+// EncryptSecret seals a secret (e.g. a TOTP seed) with AES-256-GCM.
+func EncryptSecret(plaintext string) (string, error) {
+	key, err := secretKey()
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret opens a value produced by EncryptSecret.
+func DecryptSecret(ciphertext string) (string, error) {
+	key, err := secretKey()
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("segredo cifrado inválido")
+	}
+	nonce, data := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, data, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of RFC 6238, appendix B, in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTPVectors(t *testing.T) {
+	// RFC 6238 codes truncated to six digits
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tc := range cases {
+		step, ok := ValidateTOTP(rfcSecret, tc.code, time.Unix(tc.unix, 0))
+		if !ok || step != tc.unix/totpPeriod {
+			t.Errorf("code %s at %d = (%d, %v), want step %d", tc.code, tc.unix, step, ok, tc.unix/totpPeriod)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	at := time.Unix(1111111109, 0)
+	code := "081804"
+	for _, tc := range []struct {
+		offset time.Duration
+		ok     bool
+	}{
+		{-totpPeriod * time.Second, true},
+		{totpPeriod * time.Second, true},
+		{2 * totpPeriod * time.Second, false},
+		{-2 * totpPeriod * time.Second, false},
+	} {
+		if _, ok := ValidateTOTP(rfcSecret, code, at.Add(tc.offset)); ok != tc.ok {
+			t.Errorf("offset %v: ok = %v, want %v", tc.offset, ok, tc.ok)
+		}
+	}
+}
+
+func TestValidateTOTPRejectsMalformed(t *testing.T) {
+	at := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "abcdef"} {
+		if _, ok := ValidateTOTP(rfcSecret, code, at); ok {
+			t.Errorf("code %q accepted", code)
+		}
+	}
+	if _, ok := ValidateTOTP("not base32!", "287082", at); ok {
+		t.Error("invalid secret accepted")
+	}
+}
+
+func TestGenerateTOTPSecretRoundTrip(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q decodes to %d bytes, err %v", secret, len(key), err)
+	}
+	now := time.Now()
+	if _, ok := ValidateTOTP(secret, totpCode(key, now.Unix()/totpPeriod), now); !ok {
+		t.Fatal("current code of a new secret rejected")
+	}
+}
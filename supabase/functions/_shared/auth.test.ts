@@ -0,0 +1,58 @@
+import { assertEquals, assertExists } from 'https://deno.land/std@0.208.0/assert/mod.ts';
+import { exportJWK, generateKeyPair, SignJWT } from 'https://deno.land/x/jose@v4.14.4/index.ts';
+
+// Executar com: deno test --allow-env --allow-net supabase/functions/_shared/auth.test.ts
+//
+// Os tokens do backend Go são EdDSA com kid, verificados pelo JWKS: um
+// servidor local publica a chave de teste antes de auth.ts ser carregado.
+const { publicKey, privateKey } = await generateKeyPair('EdDSA');
+const jwk = { ...(await exportJWK(publicKey)), kid: 'test-key', alg: 'EdDSA', use: 'sig' };
+const jwks = Deno.serve({ hostname: '127.0.0.1', port: 0, onListen() {} }, () => Response.json({ keys: [jwk] }));
+
+Deno.env.set('SUPABASE_URL', 'http://127.0.0.1:54321');
+Deno.env.set('SUPABASE_SERVICE_ROLE_KEY', 'service-role-key');
+Deno.env.set('GO_BACKEND_JWKS_URL', `http://127.0.0.1:${jwks.addr.port}/.well-known/jwks.json`);
+const { authenticateRequest, verifyCustomJWT } = await import('./auth.ts');
+
+// goToken assina um token como utils.GenerateJWT / GenerateMFAPendingJWT.
+function goToken(claims: Record<string, unknown>): Promise<string> {
+  return new SignJWT({ user_id: 'user-1', ...claims })
+    .setProtectedHeader({ alg: 'EdDSA', kid: 'test-key' })
+    .setSubject('user-1')
+    .setIssuer('argumentum-backend')
+    .setIssuedAt()
+    .setExpirationTime('5m')
+    .sign(privateKey);
+}
+
+Deno.test({
+  name: 'tokens restritos do backend Go não autenticam nas edge functions',
+  sanitizeOps: false,
+  sanitizeResources: false,
+  async fn(t) {
+    try {
+      await t.step('token de acesso é aceito', async () => {
+        const payload = await verifyCustomJWT(await goToken({ sid: 'session-1' }));
+        assertExists(payload);
+        assertEquals(payload.sub, 'user-1');
+      });
+
+      await t.step('token mfa_pending recebe 401', async () => {
+        const token = await goToken({ purpose: 'mfa_pending' });
+        assertEquals(await verifyCustomJWT(token), null);
+
+        const req = new Request('http://localhost/api-profile', {
+          headers: { Authorization: `Bearer ${token}` },
+        });
+        // null é o que as funções respondem com 401
+        assertEquals(await authenticateRequest(req), null);
+      });
+
+      await t.step('token de download recebe 401', async () => {
+        assertEquals(await verifyCustomJWT(await goToken({ purpose: 'download' })), null);
+      });
+    } finally {
+      await jwks.shutdown();
+    }
+  },
+});
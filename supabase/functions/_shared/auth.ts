@@ -1,10 +1,14 @@
 
 import { createClient } from 'https://esm.sh/@supabase/supabase-js@2';
-import { verify } from 'https://deno.land/x/djwt@v3.0.2/mod.ts';
+import { verify, decode } from 'https://deno.land/x/djwt@v3.0.2/mod.ts';
+import { createRemoteJWKSet, jwtVerify } from 'https://deno.land/x/jose@v4.14.4/index.ts';
 
 const SUPABASE_URL = Deno.env.get('SUPABASE_URL')!;
 const SUPABASE_SERVICE_ROLE_KEY = Deno.env.get('SUPABASE_SERVICE_ROLE_KEY')!;
 const JWT_SECRET = Deno.env.get('JWT_SECRET') || 'your-jwt-secret-key';
+// JWKS do backend Go (ex.: https://api.argumentum.com.br/.well-known/jwks.json)
+const GO_BACKEND_JWKS_URL = Deno.env.get('GO_BACKEND_JWKS_URL');
+const goBackendJWKS = GO_BACKEND_JWKS_URL ? createRemoteJWKSet(new URL(GO_BACKEND_JWKS_URL)) : null;
 
 export const supabaseAdmin = createClient(SUPABASE_URL, SUPABASE_SERVICE_ROLE_KEY, {
   auth: {
@@ -25,11 +29,34 @@ export const corsHeaders = {
   'Access-Control-Allow-Methods': 'GET, POST, PUT, DELETE, OPTIONS'
 };
 
+// Tokens com a claim "purpose" (mfa_pending, download, ...) são restritos a
+// uma única operação no backend Go e nunca valem como login completo: um
+// token mfa_pending só prova a senha, não o segundo fator.
+function isAccessToken(payload: Record<string, unknown>): boolean {
+  return payload.purpose === undefined;
+}
+
 // Função para verificar JWT customizado
 export async function verifyCustomJWT(token: string) {
   try {
     console.log(`[auth] 🔍 Verificando JWT customizado...`);
-    
+
+    // Tokens do backend Go são assinados com chave assimétrica (kid no cabeçalho)
+    const [header] = decode(token) as [{ alg?: string; kid?: string }, unknown, unknown];
+    if (header?.kid && header.alg !== 'HS256') {
+      if (!goBackendJWKS) {
+        console.error('[auth] ❌ GO_BACKEND_JWKS_URL não configurado');
+        return null;
+      }
+      const { payload } = await jwtVerify(token, goBackendJWKS, { issuer: 'argumentum-backend' });
+      if (!isAccessToken(payload)) {
+        console.error(`[auth] ❌ Token restrito (purpose: ${payload.purpose}) não é token de acesso`);
+        return null;
+      }
+      console.log(`[auth] ✅ JWT do backend Go verificado - kid: ${header.kid}`);
+      return payload;
+    }
+
     const key = await crypto.subtle.importKey(
       'raw',
       new TextEncoder().encode(JWT_SECRET),
@@ -39,6 +66,10 @@ export async function verifyCustomJWT(token: string) {
     );
 
     const payload = await verify(token, key);
+    if (!isAccessToken(payload)) {
+      console.error(`[auth] ❌ Token restrito (purpose: ${payload.purpose}) não é token de acesso`);
+      return null;
+    }
     console.log(`[auth] ✅ JWT verificado com sucesso - payload:`, payload);
     return payload;
   } catch (error) {
@@ -0,0 +1,40 @@
+-- Sessões do backend Go: cada login abre uma sessão (família de refresh tokens)
+CREATE TABLE public.auth_sessions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+  user_agent TEXT,
+  ip_address TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_auth_sessions_user_id ON public.auth_sessions(user_id);
+
+-- Refresh tokens são de uso único; apenas o hash SHA-256 é armazenado
+CREATE TABLE public.refresh_tokens (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  session_id UUID NOT NULL REFERENCES public.auth_sessions(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+  token_hash TEXT NOT NULL UNIQUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_refresh_tokens_session_id ON public.refresh_tokens(session_id);
+
+-- Enable RLS (somente o backend, via service role, acessa estas tabelas)
+ALTER TABLE public.auth_sessions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE public.refresh_tokens ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Service role can manage auth sessions"
+ON public.auth_sessions
+FOR ALL
+USING (auth.role() = 'service_role');
+
+CREATE POLICY "Service role can manage refresh tokens"
+ON public.refresh_tokens
+FOR ALL
+USING (auth.role() = 'service_role');
@@ -0,0 +1,17 @@
+-- Lista de revogação de tokens de acesso do backend Go
+-- key: "jti:<id>", "sid:<sessão>" ou "user:<id>" (tokens emitidos antes de revoked_at)
+CREATE TABLE public.revoked_tokens (
+  key TEXT PRIMARY KEY,
+  revoked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON public.revoked_tokens(expires_at);
+
+-- Enable RLS
+ALTER TABLE public.revoked_tokens ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Service role can manage revoked tokens"
+ON public.revoked_tokens
+FOR ALL
+USING (auth.role() = 'service_role');
@@ -0,0 +1,20 @@
+-- Autenticação em dois fatores (TOTP) do backend Go
+-- secret_encrypted: segredo TOTP cifrado com AES-256-GCM (MFA_ENCRYPTION_KEY)
+-- recovery_code_hashes: hashes SHA-256 dos códigos de recuperação ainda não usados
+CREATE TABLE public.user_mfa (
+  user_id UUID PRIMARY KEY REFERENCES auth.users(id) ON DELETE CASCADE,
+  secret_encrypted TEXT NOT NULL,
+  enabled BOOLEAN NOT NULL DEFAULT false,
+  recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}',
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  enabled_at TIMESTAMPTZ
+);
+
+-- Enable RLS
+ALTER TABLE public.user_mfa ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Service role can manage user mfa"
+ON public.user_mfa
+FOR ALL
+USING (auth.role() = 'service_role');
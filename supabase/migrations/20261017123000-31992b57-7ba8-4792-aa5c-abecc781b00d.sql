@@ -0,0 +1,47 @@
+-- Aceita um convite de equipe de forma atômica: valida o convite (pendente,
+-- não expirado, mesmo email), adiciona o usuário em team_members com o papel
+-- convidado e marca o convite como aceito, tudo na mesma transação.
+CREATE OR REPLACE FUNCTION public.accept_team_invite(p_invite_id uuid, p_user_id uuid, p_email text)
+RETURNS public.team_members
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $function$
+DECLARE
+  v_invite public.team_invites;
+  v_member public.team_members;
+BEGIN
+  SELECT * INTO v_invite FROM public.team_invites WHERE id = p_invite_id FOR UPDATE;
+
+  IF NOT FOUND THEN
+    RAISE EXCEPTION 'invite_not_found';
+  END IF;
+
+  IF v_invite.status <> 'pending' THEN
+    RAISE EXCEPTION 'invite_not_pending';
+  END IF;
+
+  IF v_invite.expires_at < now() THEN
+    RAISE EXCEPTION 'invite_expired';
+  END IF;
+
+  IF lower(v_invite.email) <> lower(p_email) THEN
+    RAISE EXCEPTION 'invite_email_mismatch';
+  END IF;
+
+  SELECT * INTO v_member FROM public.team_members
+  WHERE team_id = v_invite.team_id AND user_id = p_user_id;
+
+  IF NOT FOUND THEN
+    INSERT INTO public.team_members (team_id, user_id, role)
+    VALUES (v_invite.team_id, p_user_id, COALESCE(v_invite.role, 'operador'))
+    RETURNING * INTO v_member;
+  END IF;
+
+  UPDATE public.team_invites SET status = 'accepted' WHERE id = p_invite_id;
+
+  RETURN v_member;
+END;
+$function$;
+
+REVOKE ALL ON FUNCTION public.accept_team_invite(uuid, uuid, text) FROM PUBLIC, anon, authenticated;
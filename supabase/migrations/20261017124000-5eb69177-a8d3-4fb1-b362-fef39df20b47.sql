@@ -0,0 +1,27 @@
+-- Chaves de API para integrações (exportação de petições, sistemas de gestão)
+-- prefix: parte pública da chave, exibida nas listagens
+-- key_hash: hash SHA-256 da chave completa; a chave em si nunca é armazenada
+-- team_id: quando preenchido, a chave só atua sobre essa equipe
+CREATE TABLE public.api_keys (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+  team_id UUID REFERENCES public.teams(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  prefix TEXT NOT NULL,
+  key_hash TEXT NOT NULL UNIQUE,
+  scopes TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  last_used_at TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_api_keys_user_id ON public.api_keys(user_id);
+
+-- Enable RLS
+ALTER TABLE public.api_keys ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Service role can manage api keys"
+ON public.api_keys
+FOR ALL
+USING (auth.role() = 'service_role');
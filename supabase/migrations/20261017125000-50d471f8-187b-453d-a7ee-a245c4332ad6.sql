@@ -0,0 +1,35 @@
+-- Pedidos de exclusão de conta (LGPD, art. 18, VI)
+-- A conta só é apagada após scheduled_for; até lá o usuário pode cancelar.
+-- O registro sobrevive ao usuário como comprovante: user_id não referencia
+-- auth.users e o email é guardado apenas como hash.
+-- receipt: comprovante assinado (JWS) gerado quando a exclusão é concluída
+CREATE TABLE public.account_deletion_requests (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  email_sha256 TEXT NOT NULL,
+  reason TEXT,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'cancelled', 'completed')),
+  receipt_token_hash TEXT NOT NULL,
+  requested_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  scheduled_for TIMESTAMPTZ NOT NULL,
+  cancelled_at TIMESTAMPTZ,
+  completed_at TIMESTAMPTZ,
+  receipt TEXT,
+  last_error TEXT
+);
+
+CREATE UNIQUE INDEX idx_account_deletion_requests_pending
+ON public.account_deletion_requests(user_id)
+WHERE status = 'pending';
+
+CREATE INDEX idx_account_deletion_requests_due
+ON public.account_deletion_requests(scheduled_for)
+WHERE status = 'pending';
+
+-- Enable RLS
+ALTER TABLE public.account_deletion_requests ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Service role can manage account deletion requests"
+ON public.account_deletion_requests
+FOR ALL
+USING (auth.role() = 'service_role');
@@ -0,0 +1,24 @@
+-- Exportações de dados do usuário (LGPD, art. 18, V - portabilidade)
+-- file_path: caminho do ZIP no servidor que gerou a exportação
+-- expires_at: após essa data o arquivo é apagado e o registro removido
+CREATE TABLE public.data_exports (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'ready', 'failed')),
+  file_path TEXT,
+  size_bytes BIGINT NOT NULL DEFAULT 0,
+  error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  completed_at TIMESTAMPTZ,
+  expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_data_exports_user_id ON public.data_exports(user_id);
+
+-- Enable RLS
+ALTER TABLE public.data_exports ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Service role can manage data exports"
+ON public.data_exports
+FOR ALL
+USING (auth.role() = 'service_role');
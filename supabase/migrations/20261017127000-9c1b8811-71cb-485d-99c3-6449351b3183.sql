@@ -0,0 +1,25 @@
+-- Primeira resposta de cada requisição enviada com Idempotency-Key, reenviada
+-- nas repetições por 24h
+-- key: hash da chave do cliente com usuário, método e caminho
+-- request_hash: SHA-256 do corpo, para recusar a mesma chave com outro corpo
+-- status: 0 enquanto a primeira tentativa está em andamento
+CREATE TABLE public.idempotency_keys (
+  key TEXT PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+  request_hash TEXT NOT NULL,
+  status INTEGER NOT NULL DEFAULT 0,
+  content_type TEXT NOT NULL DEFAULT '',
+  body TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON public.idempotency_keys(expires_at);
+
+-- Enable RLS
+ALTER TABLE public.idempotency_keys ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY "Service role can manage idempotency keys"
+ON public.idempotency_keys
+FOR ALL
+USING (auth.role() = 'service_role');
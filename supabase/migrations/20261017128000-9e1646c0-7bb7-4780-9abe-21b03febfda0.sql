@@ -0,0 +1,56 @@
+-- Cria uma petição e debita seu custo do saldo de quem paga (o dono da equipe)
+-- de forma atômica: trava o saldo em user_tokens, recusa com
+-- insufficient_tokens quando não cobre o custo e grava a petição e a
+-- transação petition_creation na mesma transação. O trigger
+-- fn_adjust_user_tokens atualiza user_tokens a partir da transação.
+CREATE OR REPLACE FUNCTION public.create_petition(p_petition jsonb, p_payer_id uuid, p_cost integer)
+RETURNS public.petitions
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public
+AS $function$
+DECLARE
+  v_balance integer;
+  v_petition public.petitions;
+BEGIN
+  SELECT tokens INTO v_balance FROM public.user_tokens WHERE user_id = p_payer_id FOR UPDATE;
+
+  IF COALESCE(v_balance, 0) < p_cost THEN
+    RAISE EXCEPTION 'insufficient_tokens';
+  END IF;
+
+  INSERT INTO public.petitions (
+    id, user_id, team_id, title, description, content, category, legal_area,
+    petition_type, target, has_process, process_number, form_type, form_answers,
+    status, created_at, updated_at
+  )
+  SELECT
+    r.id, r.user_id, r.team_id, r.title, r.description, r.content, r.category, r.legal_area,
+    r.petition_type, r.target, r.has_process, r.process_number, r.form_type, r.form_answers,
+    r.status, r.created_at, r.updated_at
+  FROM jsonb_populate_record(NULL::public.petitions, p_petition) AS r
+  RETURNING * INTO v_petition;
+
+  INSERT INTO public.token_transactions (
+    user_id, amount, transaction_type, description, petition_id, team_id, metadata
+  )
+  VALUES (
+    p_payer_id,
+    -p_cost,
+    'petition_creation',
+    format('Criação de petição: %s', v_petition.title),
+    v_petition.id,
+    v_petition.team_id,
+    jsonb_build_object(
+      'petition_id', v_petition.id,
+      'created_by', v_petition.user_id,
+      'team_id', v_petition.team_id,
+      'cost', p_cost
+    )
+  );
+
+  RETURN v_petition;
+END;
+$function$;
+
+REVOKE ALL ON FUNCTION public.create_petition(jsonb, uuid, integer) FROM PUBLIC, anon, authenticated;
@@ -0,0 +1,4 @@
+-- Lease do worker de exclusão de contas: com várias instâncias, só quem
+-- reservar o pedido (locked_until nulo ou vencido) apaga a conta.
+ALTER TABLE public.account_deletion_requests
+  ADD COLUMN locked_until TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+-- Os ZIPs de exportação passam a ficar no R2: a coluna guarda a chave do
+-- objeto em vez de um caminho no disco do servidor que gerou a exportação.
+ALTER TABLE public.data_exports
+  RENAME COLUMN file_path TO object_key;